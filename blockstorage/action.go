@@ -0,0 +1,127 @@
+package blockstorage
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/gertd/go-openstack"
+	"github.com/gertd/go-openstack/identity"
+
+	"github.com/parnurzeal/gorequest"
+)
+
+const (
+	MigrationPolicyNever    = "never"
+	MigrationPolicyOnDemand = "on-demand"
+)
+
+type extendReq struct {
+	Extend struct {
+		NewSize int `json:"new_size"`
+	} `json:"os-extend"`
+}
+
+type retypeReq struct {
+	Retype struct {
+		NewType         string `json:"new_type"`
+		MigrationPolicy string `json:"migration_policy,omitempty"`
+	} `json:"os-retype"`
+}
+
+type bootableReq struct {
+	SetBootable struct {
+		Bootable bool `json:"bootable"`
+	} `json:"os-set_bootable"`
+}
+
+type readonlyReq struct {
+	UpdateReadonlyFlag struct {
+		Readonly bool `json:"readonly"`
+	} `json:"os-update_readonly_flag"`
+}
+
+// ExtendVolume grows the volume to newSize GiB. Extending an in-use volume
+// needs block storage API microversion 3.42, it is only requested then so
+// that available volumes can be extended on older clouds too.
+func ExtendVolume(auth identity.Auth, id string, newSize int) (err error) {
+
+	volume, err := GetVolume(auth, id)
+	if err != nil {
+		return
+	}
+
+	microversion := ""
+	if volume.Status == "in-use" {
+		microversion = "3.42"
+	}
+
+	var action extendReq
+	action.Extend.NewSize = newSize
+
+	return volumeActionReq(auth, id, action, microversion)
+}
+
+// RetypeVolume changes the volume type. With MigrationPolicyNever the retype
+// fails when the new type needs another backend, MigrationPolicyOnDemand
+// migrates the data instead.
+func RetypeVolume(auth identity.Auth, id string, volumeType string, migrationPolicy string) (err error) {
+
+	var action retypeReq
+	action.Retype.NewType = volumeType
+	action.Retype.MigrationPolicy = migrationPolicy
+
+	return volumeActionReq(auth, id, action, "")
+}
+
+func SetVolumeBootable(auth identity.Auth, id string, bootable bool) (err error) {
+
+	var action bootableReq
+	action.SetBootable.Bootable = bootable
+
+	return volumeActionReq(auth, id, action, "")
+}
+
+func SetVolumeReadonly(auth identity.Auth, id string, readonly bool) (err error) {
+
+	var action readonlyReq
+	action.UpdateReadonlyFlag.Readonly = readonly
+
+	return volumeActionReq(auth, id, action, "")
+}
+
+func volumeActionReq(auth identity.Auth, id string, action interface{}, microversion string) (err error) {
+
+	req := gorequest.New()
+
+	url := fmt.Sprintf("%s/volumes/%s/action",
+		auth.EndpointList["volumev3"],
+		id)
+
+	b, err := json.Marshal(action)
+	if err != nil {
+		return
+	}
+
+	req.Post(url).
+		Set("Content-Type", "application/json").
+		Set("Accept", "application/json").
+		Set("X-Auth-Token", auth.Access.Token.Id)
+
+	if len(microversion) > 0 {
+		req.Set("OpenStack-API-Version", "volume "+microversion)
+	}
+
+	resp, _, errs := req.Send(string(b)).End()
+
+	if errs != nil {
+		err = errs[len(errs)-1]
+		return
+	}
+
+	if err = openstack.CheckHttpResponseStatusCode(resp.StatusCode); err != nil {
+		return
+	}
+
+	err = nil
+	return
+}
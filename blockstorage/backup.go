@@ -0,0 +1,375 @@
+package blockstorage
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/url"
+
+	"github.com/gertd/go-openstack"
+	"github.com/gertd/go-openstack/identity"
+
+	"github.com/parnurzeal/gorequest"
+)
+
+type backupsResp struct {
+	Backups []Backup         `json:"backups"`
+	Links   []openstack.Link `json:"backups_links"`
+}
+
+type backupResp struct {
+	Backup Backup `json:"backup"`
+}
+
+type backupReq struct {
+	Backup BackupOpts `json:"backup"`
+}
+
+type restoreReq struct {
+	Restore RestoreOpts `json:"restore"`
+}
+
+type restoreResp struct {
+	Restore BackupRestore `json:"restore"`
+}
+
+type backupRecordResp struct {
+	BackupRecord BackupRecord `json:"backup-record"`
+}
+
+type backupRecordReq struct {
+	BackupRecord BackupRecord `json:"backup-record"`
+}
+
+// DataTimestamp is the point in time of the backed up data, it differs from
+// CreatedAt when the backup was taken from a snapshot.
+type Backup struct {
+	Id                  string            `json:"id"`
+	Name                string            `json:"name"`
+	Description         string            `json:"description"`
+	VolumeId            string            `json:"volume_id"`
+	SnapshotId          string            `json:"snapshot_id"`
+	Status              string            `json:"status"`
+	FailReason          string            `json:"fail_reason"`
+	Size                int               `json:"size"`
+	ObjectCount         int               `json:"object_count"`
+	Container           string            `json:"container"`
+	AvailabilityZone    string            `json:"availability_zone"`
+	IsIncremental       bool              `json:"is_incremental"`
+	HasDependentBackups bool              `json:"has_dependent_backups"`
+	Metadata            map[string]string `json:"metadata"`
+	Links               []openstack.Link  `json:"links"`
+	DataTimestamp       string            `json:"data_timestamp"`
+	CreatedAt           string            `json:"created_at"`
+	UpdatedAt           string            `json:"updated_at"`
+}
+
+// BackupOpts is used to create backups, empty fields are not sent.
+// Incremental backs up the changes since the latest backup of the volume,
+// Force allows backing up an in-use volume and SnapshotId backs up the
+// snapshot instead of the current volume data.
+type BackupOpts struct {
+	VolumeId    string `json:"volume_id"`
+	Name        string `json:"name,omitempty"`
+	Description string `json:"description,omitempty"`
+	Container   string `json:"container,omitempty"`
+	Incremental bool   `json:"incremental,omitempty"`
+	Force       bool   `json:"force,omitempty"`
+	SnapshotId  string `json:"snapshot_id,omitempty"`
+}
+
+// BackupFilter narrows GetBackups, empty fields are not sent. AllTenants
+// requires admin rights.
+type BackupFilter struct {
+	VolumeId   string
+	Name       string
+	Status     string
+	AllTenants bool
+}
+
+// RestoreOpts selects the restore target: an existing VolumeId is
+// overwritten, it must be available and at least as large as the backup.
+// Without VolumeId a new volume named Name is created.
+type RestoreOpts struct {
+	VolumeId string `json:"volume_id,omitempty"`
+	Name     string `json:"name,omitempty"`
+}
+
+type BackupRestore struct {
+	BackupId   string `json:"backup_id"`
+	VolumeId   string `json:"volume_id"`
+	VolumeName string `json:"volume_name"`
+}
+
+// BackupRecord holds the backup metadata needed to import a backup in
+// another deployment sharing the backup store, BackupURL is opaque.
+type BackupRecord struct {
+	BackupService string `json:"backup_service"`
+	BackupURL     string `json:"backup_url"`
+}
+
+func GetBackups(auth identity.Auth, filter BackupFilter) (backups []Backup, err error) {
+
+	query := url.Values{}
+	if len(filter.VolumeId) > 0 {
+		query.Set("volume_id", filter.VolumeId)
+	}
+	if len(filter.Name) > 0 {
+		query.Set("name", filter.Name)
+	}
+	if len(filter.Status) > 0 {
+		query.Set("status", filter.Status)
+	}
+	if filter.AllTenants {
+		query.Set("all_tenants", "1")
+	}
+
+	reqUrl := fmt.Sprintf("%s/backups/detail?%s",
+		auth.EndpointList["volumev3"],
+		query.Encode())
+
+	for len(reqUrl) > 0 {
+		req := gorequest.New()
+
+		resp, body, errs := req.Get(reqUrl).
+			Set("Content-Type", "application/json").
+			Set("Accept", "application/json").
+			Set("X-Auth-Token", auth.Access.Token.Id).
+			End()
+
+		if errs != nil {
+			err = errs[len(errs)-1]
+			return
+		}
+
+		if err = openstack.CheckHttpResponseStatusCode(resp.StatusCode); err != nil {
+			return
+		}
+
+		var r = backupsResp{}
+		if err = json.Unmarshal([]byte(body), &r); err != nil {
+			return
+		}
+
+		backups = append(backups, r.Backups...)
+
+		reqUrl = nextLink(r.Links)
+	}
+
+	err = nil
+	return
+}
+
+func GetBackup(auth identity.Auth, id string) (backup Backup, err error) {
+
+	req := gorequest.New()
+
+	url := fmt.Sprintf("%s/backups/%s",
+		auth.EndpointList["volumev3"],
+		id)
+
+	resp, body, errs := req.Get(url).
+		Set("Content-Type", "application/json").
+		Set("Accept", "application/json").
+		Set("X-Auth-Token", auth.Access.Token.Id).
+		End()
+
+	if errs != nil {
+		err = errs[len(errs)-1]
+		return
+	}
+
+	if err = openstack.CheckHttpResponseStatusCode(resp.StatusCode); err != nil {
+		return
+	}
+
+	var r = backupResp{}
+	if err = json.Unmarshal([]byte(body), &r); err != nil {
+		return
+	}
+
+	backup = r.Backup
+	err = nil
+	return
+}
+
+// CreateBackup starts a backup, it is complete once its status is
+// available, see WaitForBackupStatus.
+func CreateBackup(auth identity.Auth, opts BackupOpts) (backup Backup, err error) {
+
+	req := gorequest.New()
+
+	url := fmt.Sprintf("%s/backups",
+		auth.EndpointList["volumev3"])
+
+	b, err := json.Marshal(backupReq{opts})
+	if err != nil {
+		return
+	}
+
+	resp, body, errs := req.Post(url).
+		Set("Content-Type", "application/json").
+		Set("Accept", "application/json").
+		Set("X-Auth-Token", auth.Access.Token.Id).
+		Send(string(b)).
+		End()
+
+	if errs != nil {
+		err = errs[len(errs)-1]
+		return
+	}
+
+	if err = openstack.CheckHttpResponseStatusCode(resp.StatusCode); err != nil {
+		return
+	}
+
+	var r = backupResp{}
+	if err = json.Unmarshal([]byte(body), &r); err != nil {
+		return
+	}
+
+	backup = r.Backup
+	err = nil
+	return
+}
+
+// DeleteBackup deletes the backup. Backups that incremental backups depend
+// on can only be deleted after them.
+func DeleteBackup(auth identity.Auth, id string) (err error) {
+
+	req := gorequest.New()
+
+	url := fmt.Sprintf("%s/backups/%s",
+		auth.EndpointList["volumev3"],
+		id)
+
+	resp, _, errs := req.Delete(url).
+		Set("Content-Type", "application/json").
+		Set("Accept", "application/json").
+		Set("X-Auth-Token", auth.Access.Token.Id).
+		End()
+
+	if errs != nil {
+		err = errs[len(errs)-1]
+		return
+	}
+
+	if err = openstack.CheckHttpResponseStatusCode(resp.StatusCode); err != nil {
+		return
+	}
+
+	err = nil
+	return
+}
+
+func RestoreBackup(auth identity.Auth, id string, opts RestoreOpts) (restore BackupRestore, err error) {
+
+	req := gorequest.New()
+
+	url := fmt.Sprintf("%s/backups/%s/restore",
+		auth.EndpointList["volumev3"],
+		id)
+
+	b, err := json.Marshal(restoreReq{opts})
+	if err != nil {
+		return
+	}
+
+	resp, body, errs := req.Post(url).
+		Set("Content-Type", "application/json").
+		Set("Accept", "application/json").
+		Set("X-Auth-Token", auth.Access.Token.Id).
+		Send(string(b)).
+		End()
+
+	if errs != nil {
+		err = errs[len(errs)-1]
+		return
+	}
+
+	if err = openstack.CheckHttpResponseStatusCode(resp.StatusCode); err != nil {
+		return
+	}
+
+	var r = restoreResp{}
+	if err = json.Unmarshal([]byte(body), &r); err != nil {
+		return
+	}
+
+	restore = r.Restore
+	err = nil
+	return
+}
+
+// ExportBackupRecord requires admin rights.
+func ExportBackupRecord(auth identity.Auth, id string) (record BackupRecord, err error) {
+
+	req := gorequest.New()
+
+	url := fmt.Sprintf("%s/backups/%s/export_record",
+		auth.EndpointList["volumev3"],
+		id)
+
+	resp, body, errs := req.Get(url).
+		Set("Content-Type", "application/json").
+		Set("Accept", "application/json").
+		Set("X-Auth-Token", auth.Access.Token.Id).
+		End()
+
+	if errs != nil {
+		err = errs[len(errs)-1]
+		return
+	}
+
+	if err = openstack.CheckHttpResponseStatusCode(resp.StatusCode); err != nil {
+		return
+	}
+
+	var r = backupRecordResp{}
+	if err = json.Unmarshal([]byte(body), &r); err != nil {
+		return
+	}
+
+	record = r.BackupRecord
+	err = nil
+	return
+}
+
+// ImportBackupRecord requires admin rights, the imported backup only has
+// its Id, Name and Links set.
+func ImportBackupRecord(auth identity.Auth, record BackupRecord) (backup Backup, err error) {
+
+	req := gorequest.New()
+
+	url := fmt.Sprintf("%s/backups/import_record",
+		auth.EndpointList["volumev3"])
+
+	b, err := json.Marshal(backupRecordReq{record})
+	if err != nil {
+		return
+	}
+
+	resp, body, errs := req.Post(url).
+		Set("Content-Type", "application/json").
+		Set("Accept", "application/json").
+		Set("X-Auth-Token", auth.Access.Token.Id).
+		Send(string(b)).
+		End()
+
+	if errs != nil {
+		err = errs[len(errs)-1]
+		return
+	}
+
+	if err = openstack.CheckHttpResponseStatusCode(resp.StatusCode); err != nil {
+		return
+	}
+
+	var r = backupResp{}
+	if err = json.Unmarshal([]byte(body), &r); err != nil {
+		return
+	}
+
+	backup = r.Backup
+	err = nil
+	return
+}
@@ -0,0 +1,161 @@
+package blockstorage
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/gertd/go-openstack"
+	"github.com/gertd/go-openstack/identity"
+
+	"github.com/parnurzeal/gorequest"
+)
+
+type metadataResp struct {
+	Metadata map[string]string `json:"metadata"`
+}
+
+type metadataReq struct {
+	Metadata map[string]string `json:"metadata"`
+}
+
+func GetVolumeMetadata(auth identity.Auth, id string) (metadata map[string]string, err error) {
+
+	req := gorequest.New()
+
+	url := fmt.Sprintf("%s/volumes/%s/metadata",
+		auth.EndpointList["volumev3"],
+		id)
+
+	resp, body, errs := req.Get(url).
+		Set("Content-Type", "application/json").
+		Set("Accept", "application/json").
+		Set("X-Auth-Token", auth.Access.Token.Id).
+		End()
+
+	if errs != nil {
+		err = errs[len(errs)-1]
+		return
+	}
+
+	if err = openstack.CheckHttpResponseStatusCode(resp.StatusCode); err != nil {
+		return
+	}
+
+	var r = metadataResp{}
+	if err = json.Unmarshal([]byte(body), &r); err != nil {
+		return
+	}
+
+	metadata = r.Metadata
+	err = nil
+	return
+}
+
+// SetVolumeMetadata replaces all metadata of the volume with metadata.
+func SetVolumeMetadata(auth identity.Auth, id string, metadata map[string]string) (result map[string]string, err error) {
+
+	req := gorequest.New()
+
+	url := fmt.Sprintf("%s/volumes/%s/metadata",
+		auth.EndpointList["volumev3"],
+		id)
+
+	b, err := json.Marshal(metadataReq{metadata})
+	if err != nil {
+		return
+	}
+
+	resp, body, errs := req.Put(url).
+		Set("Content-Type", "application/json").
+		Set("Accept", "application/json").
+		Set("X-Auth-Token", auth.Access.Token.Id).
+		Send(string(b)).
+		End()
+
+	if errs != nil {
+		err = errs[len(errs)-1]
+		return
+	}
+
+	if err = openstack.CheckHttpResponseStatusCode(resp.StatusCode); err != nil {
+		return
+	}
+
+	var r = metadataResp{}
+	if err = json.Unmarshal([]byte(body), &r); err != nil {
+		return
+	}
+
+	result = r.Metadata
+	err = nil
+	return
+}
+
+// UpdateVolumeMetadata adds metadata to the volume, existing keys are
+// overwritten and the others kept. The merged metadata is returned.
+func UpdateVolumeMetadata(auth identity.Auth, id string, metadata map[string]string) (result map[string]string, err error) {
+
+	req := gorequest.New()
+
+	url := fmt.Sprintf("%s/volumes/%s/metadata",
+		auth.EndpointList["volumev3"],
+		id)
+
+	b, err := json.Marshal(metadataReq{metadata})
+	if err != nil {
+		return
+	}
+
+	resp, body, errs := req.Post(url).
+		Set("Content-Type", "application/json").
+		Set("Accept", "application/json").
+		Set("X-Auth-Token", auth.Access.Token.Id).
+		Send(string(b)).
+		End()
+
+	if errs != nil {
+		err = errs[len(errs)-1]
+		return
+	}
+
+	if err = openstack.CheckHttpResponseStatusCode(resp.StatusCode); err != nil {
+		return
+	}
+
+	var r = metadataResp{}
+	if err = json.Unmarshal([]byte(body), &r); err != nil {
+		return
+	}
+
+	result = r.Metadata
+	err = nil
+	return
+}
+
+func DeleteVolumeMetadata(auth identity.Auth, id string, key string) (err error) {
+
+	req := gorequest.New()
+
+	url := fmt.Sprintf("%s/volumes/%s/metadata/%s",
+		auth.EndpointList["volumev3"],
+		id,
+		key)
+
+	resp, _, errs := req.Delete(url).
+		Set("Content-Type", "application/json").
+		Set("Accept", "application/json").
+		Set("X-Auth-Token", auth.Access.Token.Id).
+		End()
+
+	if errs != nil {
+		err = errs[len(errs)-1]
+		return
+	}
+
+	if err = openstack.CheckHttpResponseStatusCode(resp.StatusCode); err != nil {
+		return
+	}
+
+	err = nil
+	return
+}
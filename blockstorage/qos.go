@@ -0,0 +1,378 @@
+package blockstorage
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/url"
+
+	"github.com/gertd/go-openstack"
+	"github.com/gertd/go-openstack/identity"
+
+	"github.com/parnurzeal/gorequest"
+)
+
+const (
+	QoSConsumerFrontEnd = "front-end"
+	QoSConsumerBackEnd  = "back-end"
+	QoSConsumerBoth     = "both"
+)
+
+type qosSpecsListResp struct {
+	QoSSpecs []QoSSpec `json:"qos_specs"`
+}
+
+type qosSpecResp struct {
+	QoSSpecs QoSSpec `json:"qos_specs"`
+}
+
+type qosAssociationsResp struct {
+	QoSAssociations []QoSAssociation `json:"qos_associations"`
+}
+
+type qosKeysReq struct {
+	Keys []string `json:"keys"`
+}
+
+// Consumer tells where the limits in Specs, such as total_iops_sec, are
+// enforced: by the hypervisor (front-end), the storage backend (back-end)
+// or both.
+type QoSSpec struct {
+	Id       string            `json:"id"`
+	Name     string            `json:"name"`
+	Consumer string            `json:"consumer"`
+	Specs    map[string]string `json:"specs"`
+}
+
+type QoSAssociation struct {
+	AssociationType string `json:"association_type"`
+	Id              string `json:"id"`
+	Name            string `json:"name"`
+}
+
+func GetQoSSpecs(auth identity.Auth) (qosSpecs []QoSSpec, err error) {
+
+	req := gorequest.New()
+
+	reqUrl := fmt.Sprintf("%s/qos-specs",
+		auth.EndpointList["volumev3"])
+
+	resp, body, errs := req.Get(reqUrl).
+		Set("Content-Type", "application/json").
+		Set("Accept", "application/json").
+		Set("X-Auth-Token", auth.Access.Token.Id).
+		End()
+
+	if errs != nil {
+		err = errs[len(errs)-1]
+		return
+	}
+
+	if err = openstack.CheckHttpResponseStatusCode(resp.StatusCode); err != nil {
+		return
+	}
+
+	var r = qosSpecsListResp{}
+	if err = json.Unmarshal([]byte(body), &r); err != nil {
+		return
+	}
+
+	qosSpecs = r.QoSSpecs
+	err = nil
+	return
+}
+
+func GetQoSSpec(auth identity.Auth, id string) (qosSpec QoSSpec, err error) {
+
+	req := gorequest.New()
+
+	reqUrl := fmt.Sprintf("%s/qos-specs/%s",
+		auth.EndpointList["volumev3"],
+		id)
+
+	resp, body, errs := req.Get(reqUrl).
+		Set("Content-Type", "application/json").
+		Set("Accept", "application/json").
+		Set("X-Auth-Token", auth.Access.Token.Id).
+		End()
+
+	if errs != nil {
+		err = errs[len(errs)-1]
+		return
+	}
+
+	if err = openstack.CheckHttpResponseStatusCode(resp.StatusCode); err != nil {
+		return
+	}
+
+	var r = qosSpecResp{}
+	if err = json.Unmarshal([]byte(body), &r); err != nil {
+		return
+	}
+
+	qosSpec = r.QoSSpecs
+	err = nil
+	return
+}
+
+// CreateQoSSpec creates a QoS spec, an empty consumer defaults to back-end.
+func CreateQoSSpec(auth identity.Auth, name string, consumer string, specs map[string]string) (qosSpec QoSSpec, err error) {
+
+	req := gorequest.New()
+
+	reqUrl := fmt.Sprintf("%s/qos-specs",
+		auth.EndpointList["volumev3"])
+
+	attrs := map[string]string{"name": name}
+	if len(consumer) > 0 {
+		attrs["consumer"] = consumer
+	}
+	for k, v := range specs {
+		attrs[k] = v
+	}
+
+	b, err := json.Marshal(map[string]interface{}{"qos_specs": attrs})
+	if err != nil {
+		return
+	}
+
+	resp, body, errs := req.Post(reqUrl).
+		Set("Content-Type", "application/json").
+		Set("Accept", "application/json").
+		Set("X-Auth-Token", auth.Access.Token.Id).
+		Send(string(b)).
+		End()
+
+	if errs != nil {
+		err = errs[len(errs)-1]
+		return
+	}
+
+	if err = openstack.CheckHttpResponseStatusCode(resp.StatusCode); err != nil {
+		return
+	}
+
+	var r = qosSpecResp{}
+	if err = json.Unmarshal([]byte(body), &r); err != nil {
+		return
+	}
+
+	qosSpec = r.QoSSpecs
+	err = nil
+	return
+}
+
+// SetQoSSpecKeys adds specs to the QoS spec, existing keys are overwritten
+// and the others kept.
+func SetQoSSpecKeys(auth identity.Auth, id string, specs map[string]string) (err error) {
+
+	req := gorequest.New()
+
+	reqUrl := fmt.Sprintf("%s/qos-specs/%s",
+		auth.EndpointList["volumev3"],
+		id)
+
+	b, err := json.Marshal(map[string]interface{}{"qos_specs": specs})
+	if err != nil {
+		return
+	}
+
+	resp, _, errs := req.Put(reqUrl).
+		Set("Content-Type", "application/json").
+		Set("Accept", "application/json").
+		Set("X-Auth-Token", auth.Access.Token.Id).
+		Send(string(b)).
+		End()
+
+	if errs != nil {
+		err = errs[len(errs)-1]
+		return
+	}
+
+	if err = openstack.CheckHttpResponseStatusCode(resp.StatusCode); err != nil {
+		return
+	}
+
+	err = nil
+	return
+}
+
+func DeleteQoSSpecKeys(auth identity.Auth, id string, keys []string) (err error) {
+
+	req := gorequest.New()
+
+	reqUrl := fmt.Sprintf("%s/qos-specs/%s/delete_keys",
+		auth.EndpointList["volumev3"],
+		id)
+
+	b, err := json.Marshal(qosKeysReq{keys})
+	if err != nil {
+		return
+	}
+
+	resp, _, errs := req.Put(reqUrl).
+		Set("Content-Type", "application/json").
+		Set("Accept", "application/json").
+		Set("X-Auth-Token", auth.Access.Token.Id).
+		Send(string(b)).
+		End()
+
+	if errs != nil {
+		err = errs[len(errs)-1]
+		return
+	}
+
+	if err = openstack.CheckHttpResponseStatusCode(resp.StatusCode); err != nil {
+		return
+	}
+
+	err = nil
+	return
+}
+
+// DeleteQoSSpec deletes the QoS spec, force is needed while it is still
+// associated with volume types.
+func DeleteQoSSpec(auth identity.Auth, id string, force bool) (err error) {
+
+	req := gorequest.New()
+
+	reqUrl := fmt.Sprintf("%s/qos-specs/%s?force=%t",
+		auth.EndpointList["volumev3"],
+		id,
+		force)
+
+	resp, _, errs := req.Delete(reqUrl).
+		Set("Content-Type", "application/json").
+		Set("Accept", "application/json").
+		Set("X-Auth-Token", auth.Access.Token.Id).
+		End()
+
+	if errs != nil {
+		err = errs[len(errs)-1]
+		return
+	}
+
+	if err = openstack.CheckHttpResponseStatusCode(resp.StatusCode); err != nil {
+		return
+	}
+
+	err = nil
+	return
+}
+
+func GetQoSAssociations(auth identity.Auth, id string) (associations []QoSAssociation, err error) {
+
+	req := gorequest.New()
+
+	reqUrl := fmt.Sprintf("%s/qos-specs/%s/associations",
+		auth.EndpointList["volumev3"],
+		id)
+
+	resp, body, errs := req.Get(reqUrl).
+		Set("Content-Type", "application/json").
+		Set("Accept", "application/json").
+		Set("X-Auth-Token", auth.Access.Token.Id).
+		End()
+
+	if errs != nil {
+		err = errs[len(errs)-1]
+		return
+	}
+
+	if err = openstack.CheckHttpResponseStatusCode(resp.StatusCode); err != nil {
+		return
+	}
+
+	var r = qosAssociationsResp{}
+	if err = json.Unmarshal([]byte(body), &r); err != nil {
+		return
+	}
+
+	associations = r.QoSAssociations
+	err = nil
+	return
+}
+
+// AssociateQoSSpec applies the QoS spec to the volumes of the volume type, a
+// volume type has at most one QoS spec.
+func AssociateQoSSpec(auth identity.Auth, id string, volumeTypeId string) (err error) {
+
+	req := gorequest.New()
+
+	reqUrl := fmt.Sprintf("%s/qos-specs/%s/associate?vol_type_id=%s",
+		auth.EndpointList["volumev3"],
+		id,
+		url.QueryEscape(volumeTypeId))
+
+	resp, _, errs := req.Get(reqUrl).
+		Set("Content-Type", "application/json").
+		Set("Accept", "application/json").
+		Set("X-Auth-Token", auth.Access.Token.Id).
+		End()
+
+	if errs != nil {
+		err = errs[len(errs)-1]
+		return
+	}
+
+	if err = openstack.CheckHttpResponseStatusCode(resp.StatusCode); err != nil {
+		return
+	}
+
+	err = nil
+	return
+}
+
+func DisassociateQoSSpec(auth identity.Auth, id string, volumeTypeId string) (err error) {
+
+	req := gorequest.New()
+
+	reqUrl := fmt.Sprintf("%s/qos-specs/%s/disassociate?vol_type_id=%s",
+		auth.EndpointList["volumev3"],
+		id,
+		url.QueryEscape(volumeTypeId))
+
+	resp, _, errs := req.Get(reqUrl).
+		Set("Content-Type", "application/json").
+		Set("Accept", "application/json").
+		Set("X-Auth-Token", auth.Access.Token.Id).
+		End()
+
+	if errs != nil {
+		err = errs[len(errs)-1]
+		return
+	}
+
+	if err = openstack.CheckHttpResponseStatusCode(resp.StatusCode); err != nil {
+		return
+	}
+
+	err = nil
+	return
+}
+
+func DisassociateQoSSpecAll(auth identity.Auth, id string) (err error) {
+
+	req := gorequest.New()
+
+	reqUrl := fmt.Sprintf("%s/qos-specs/%s/disassociate_all",
+		auth.EndpointList["volumev3"],
+		id)
+
+	resp, _, errs := req.Get(reqUrl).
+		Set("Content-Type", "application/json").
+		Set("Accept", "application/json").
+		Set("X-Auth-Token", auth.Access.Token.Id).
+		End()
+
+	if errs != nil {
+		err = errs[len(errs)-1]
+		return
+	}
+
+	if err = openstack.CheckHttpResponseStatusCode(resp.StatusCode); err != nil {
+		return
+	}
+
+	err = nil
+	return
+}
@@ -0,0 +1,100 @@
+package blockstorage
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/gertd/go-openstack/identity"
+)
+
+// PruneResult lists the backups PruneBackups deleted, and the ones it had
+// to keep because a kept incremental backup still depends on them.
+type PruneResult struct {
+	Deleted []Backup
+	Skipped []Backup
+}
+
+// ByAge sorts backups from newest to oldest on their data timestamp.
+type ByAge []Backup
+
+func (a ByAge) Len() int      { return len(a) }
+func (a ByAge) Swap(i, j int) { a[i], a[j] = a[j], a[i] }
+func (a ByAge) Less(i, j int) bool {
+	return backupTimestamp(a[i]) > backupTimestamp(a[j])
+}
+
+// PruneBackups keeps the keep most recent available backups of every volume
+// and deletes the older ones, newest first so incremental backups go before
+// the backups they depend on. Every deletion is awaited, up to timeout each,
+// before the next one; a timeout of zero waits forever. Backups in another
+// status than available are left alone and do not count towards keep.
+func PruneBackups(auth identity.Auth, keep int, timeout time.Duration) (result PruneResult, err error) {
+
+	if keep < 1 {
+		err = errors.New(fmt.Sprintf("keep must be at least 1, got %d", keep))
+		return
+	}
+
+	backups, err := GetBackups(auth, BackupFilter{Status: "available"})
+	if err != nil {
+		return
+	}
+
+	byVolume := make(map[string][]Backup)
+	volumes := []string{}
+	for _, v := range backups {
+		if v.Status != "available" {
+			continue
+		}
+		if _, ok := byVolume[v.VolumeId]; !ok {
+			volumes = append(volumes, v.VolumeId)
+		}
+		byVolume[v.VolumeId] = append(byVolume[v.VolumeId], v)
+	}
+	sort.Strings(volumes)
+
+	for _, volumeId := range volumes {
+		list := byVolume[volumeId]
+		if len(list) <= keep {
+			continue
+		}
+
+		sort.Sort(ByAge(list))
+
+		for _, v := range list[keep:] {
+			backup, e := GetBackup(auth, v.Id)
+			if e != nil {
+				err = e
+				return
+			}
+
+			if backup.HasDependentBackups {
+				result.Skipped = append(result.Skipped, backup)
+				continue
+			}
+
+			if err = DeleteBackup(auth, backup.Id); err != nil {
+				return
+			}
+			if err = WaitForBackupDeleted(auth, backup.Id, timeout); err != nil {
+				return
+			}
+
+			result.Deleted = append(result.Deleted, backup)
+		}
+	}
+
+	err = nil
+	return
+}
+
+func backupTimestamp(backup Backup) string {
+
+	if len(backup.DataTimestamp) > 0 {
+		return backup.DataTimestamp
+	}
+
+	return backup.CreatedAt
+}
@@ -0,0 +1,248 @@
+package blockstorage
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/url"
+
+	"github.com/gertd/go-openstack"
+	"github.com/gertd/go-openstack/identity"
+
+	"github.com/parnurzeal/gorequest"
+)
+
+type snapshotsResp struct {
+	Snapshots []Snapshot       `json:"snapshots"`
+	Links     []openstack.Link `json:"snapshots_links"`
+}
+
+type snapshotResp struct {
+	Snapshot Snapshot `json:"snapshot"`
+}
+
+type snapshotReq struct {
+	Snapshot SnapshotOpts `json:"snapshot"`
+}
+
+type Snapshot struct {
+	Id          string            `json:"id"`
+	Name        string            `json:"name"`
+	Description string            `json:"description"`
+	VolumeId    string            `json:"volume_id"`
+	Status      string            `json:"status"`
+	Size        int               `json:"size"`
+	Metadata    map[string]string `json:"metadata"`
+	Progress    string            `json:"os-extended-snapshot-attributes:progress"`
+	ProjectId   string            `json:"os-extended-snapshot-attributes:project_id"`
+	CreatedAt   string            `json:"created_at"`
+	UpdatedAt   string            `json:"updated_at"`
+}
+
+// SnapshotOpts is used to create and update snapshots, nil and empty fields
+// are not sent. VolumeId, Force and Metadata are only used on creation, Force
+// allows snapshotting an in-use volume.
+type SnapshotOpts struct {
+	VolumeId    string            `json:"volume_id,omitempty"`
+	Name        *string           `json:"name,omitempty"`
+	Description *string           `json:"description,omitempty"`
+	Force       bool              `json:"force,omitempty"`
+	Metadata    map[string]string `json:"metadata,omitempty"`
+}
+
+// SnapshotFilter narrows GetSnapshots, empty fields are not sent.
+// AllTenants requires admin rights.
+type SnapshotFilter struct {
+	VolumeId   string
+	Name       string
+	Status     string
+	AllTenants bool
+}
+
+func GetSnapshots(auth identity.Auth, filter SnapshotFilter) (snapshots []Snapshot, err error) {
+
+	query := url.Values{}
+	if len(filter.VolumeId) > 0 {
+		query.Set("volume_id", filter.VolumeId)
+	}
+	if len(filter.Name) > 0 {
+		query.Set("name", filter.Name)
+	}
+	if len(filter.Status) > 0 {
+		query.Set("status", filter.Status)
+	}
+	if filter.AllTenants {
+		query.Set("all_tenants", "1")
+	}
+
+	reqUrl := fmt.Sprintf("%s/snapshots/detail?%s",
+		auth.EndpointList["volumev3"],
+		query.Encode())
+
+	for len(reqUrl) > 0 {
+		req := gorequest.New()
+
+		resp, body, errs := req.Get(reqUrl).
+			Set("Content-Type", "application/json").
+			Set("Accept", "application/json").
+			Set("X-Auth-Token", auth.Access.Token.Id).
+			End()
+
+		if errs != nil {
+			err = errs[len(errs)-1]
+			return
+		}
+
+		if err = openstack.CheckHttpResponseStatusCode(resp.StatusCode); err != nil {
+			return
+		}
+
+		var r = snapshotsResp{}
+		if err = json.Unmarshal([]byte(body), &r); err != nil {
+			return
+		}
+
+		snapshots = append(snapshots, r.Snapshots...)
+
+		reqUrl = nextLink(r.Links)
+	}
+
+	err = nil
+	return
+}
+
+func GetSnapshot(auth identity.Auth, id string) (snapshot Snapshot, err error) {
+
+	req := gorequest.New()
+
+	url := fmt.Sprintf("%s/snapshots/%s",
+		auth.EndpointList["volumev3"],
+		id)
+
+	resp, body, errs := req.Get(url).
+		Set("Content-Type", "application/json").
+		Set("Accept", "application/json").
+		Set("X-Auth-Token", auth.Access.Token.Id).
+		End()
+
+	if errs != nil {
+		err = errs[len(errs)-1]
+		return
+	}
+
+	if err = openstack.CheckHttpResponseStatusCode(resp.StatusCode); err != nil {
+		return
+	}
+
+	var r = snapshotResp{}
+	if err = json.Unmarshal([]byte(body), &r); err != nil {
+		return
+	}
+
+	snapshot = r.Snapshot
+	err = nil
+	return
+}
+
+func CreateSnapshot(auth identity.Auth, opts SnapshotOpts) (snapshot Snapshot, err error) {
+
+	req := gorequest.New()
+
+	url := fmt.Sprintf("%s/snapshots",
+		auth.EndpointList["volumev3"])
+
+	b, err := json.Marshal(snapshotReq{opts})
+	if err != nil {
+		return
+	}
+
+	resp, body, errs := req.Post(url).
+		Set("Content-Type", "application/json").
+		Set("Accept", "application/json").
+		Set("X-Auth-Token", auth.Access.Token.Id).
+		Send(string(b)).
+		End()
+
+	if errs != nil {
+		err = errs[len(errs)-1]
+		return
+	}
+
+	if err = openstack.CheckHttpResponseStatusCode(resp.StatusCode); err != nil {
+		return
+	}
+
+	var r = snapshotResp{}
+	if err = json.Unmarshal([]byte(body), &r); err != nil {
+		return
+	}
+
+	snapshot = r.Snapshot
+	err = nil
+	return
+}
+
+func UpdateSnapshot(auth identity.Auth, id string, opts SnapshotOpts) (snapshot Snapshot, err error) {
+
+	req := gorequest.New()
+
+	url := fmt.Sprintf("%s/snapshots/%s",
+		auth.EndpointList["volumev3"],
+		id)
+
+	b, err := json.Marshal(snapshotReq{opts})
+	if err != nil {
+		return
+	}
+
+	resp, body, errs := req.Put(url).
+		Set("Content-Type", "application/json").
+		Set("Accept", "application/json").
+		Set("X-Auth-Token", auth.Access.Token.Id).
+		Send(string(b)).
+		End()
+
+	if errs != nil {
+		err = errs[len(errs)-1]
+		return
+	}
+
+	if err = openstack.CheckHttpResponseStatusCode(resp.StatusCode); err != nil {
+		return
+	}
+
+	var r = snapshotResp{}
+	if err = json.Unmarshal([]byte(body), &r); err != nil {
+		return
+	}
+
+	snapshot = r.Snapshot
+	err = nil
+	return
+}
+
+func DeleteSnapshot(auth identity.Auth, id string) (err error) {
+
+	req := gorequest.New()
+
+	url := fmt.Sprintf("%s/snapshots/%s",
+		auth.EndpointList["volumev3"],
+		id)
+
+	resp, _, errs := req.Delete(url).
+		Set("Content-Type", "application/json").
+		Set("Accept", "application/json").
+		Set("X-Auth-Token", auth.Access.Token.Id).
+		End()
+
+	if errs != nil {
+		err = errs[len(errs)-1]
+		return
+	}
+
+	if err = openstack.CheckHttpResponseStatusCode(resp.StatusCode); err != nil {
+		return
+	}
+
+	err = nil
+	return
+}
@@ -0,0 +1,223 @@
+package blockstorage
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/gertd/go-openstack"
+	"github.com/gertd/go-openstack/identity"
+
+	"github.com/parnurzeal/gorequest"
+)
+
+type transfersResp struct {
+	Transfers []Transfer `json:"transfers"`
+}
+
+type transferResp struct {
+	Transfer Transfer `json:"transfer"`
+}
+
+type transferReq struct {
+	Transfer struct {
+		VolumeId string `json:"volume_id"`
+		Name     string `json:"name,omitempty"`
+	} `json:"transfer"`
+}
+
+type acceptReq struct {
+	Accept struct {
+		AuthKey string `json:"auth_key"`
+	} `json:"accept"`
+}
+
+// AuthKey is only returned by CreateTransfer, it must be handed to the
+// receiving project together with the transfer Id.
+type Transfer struct {
+	Id        string           `json:"id"`
+	Name      string           `json:"name"`
+	VolumeId  string           `json:"volume_id"`
+	AuthKey   string           `json:"auth_key"`
+	Links     []openstack.Link `json:"links"`
+	CreatedAt string           `json:"created_at"`
+}
+
+// CreateTransfer offers the volume to another project, the volume must be
+// available and stays in the awaiting-transfer status until the transfer is
+// accepted or deleted.
+func CreateTransfer(auth identity.Auth, volumeId string, name string) (transfer Transfer, err error) {
+
+	req := gorequest.New()
+
+	url := fmt.Sprintf("%s/os-volume-transfer",
+		auth.EndpointList["volumev3"])
+
+	var t transferReq
+	t.Transfer.VolumeId = volumeId
+	t.Transfer.Name = name
+
+	b, err := json.Marshal(t)
+	if err != nil {
+		return
+	}
+
+	resp, body, errs := req.Post(url).
+		Set("Content-Type", "application/json").
+		Set("Accept", "application/json").
+		Set("X-Auth-Token", auth.Access.Token.Id).
+		Send(string(b)).
+		End()
+
+	if errs != nil {
+		err = errs[len(errs)-1]
+		return
+	}
+
+	if err = openstack.CheckHttpResponseStatusCode(resp.StatusCode); err != nil {
+		return
+	}
+
+	var r = transferResp{}
+	if err = json.Unmarshal([]byte(body), &r); err != nil {
+		return
+	}
+
+	transfer = r.Transfer
+	err = nil
+	return
+}
+
+func GetTransfers(auth identity.Auth) (transfers []Transfer, err error) {
+
+	req := gorequest.New()
+
+	url := fmt.Sprintf("%s/os-volume-transfer/detail",
+		auth.EndpointList["volumev3"])
+
+	resp, body, errs := req.Get(url).
+		Set("Content-Type", "application/json").
+		Set("Accept", "application/json").
+		Set("X-Auth-Token", auth.Access.Token.Id).
+		End()
+
+	if errs != nil {
+		err = errs[len(errs)-1]
+		return
+	}
+
+	if err = openstack.CheckHttpResponseStatusCode(resp.StatusCode); err != nil {
+		return
+	}
+
+	var r = transfersResp{}
+	if err = json.Unmarshal([]byte(body), &r); err != nil {
+		return
+	}
+
+	transfers = r.Transfers
+	err = nil
+	return
+}
+
+func GetTransfer(auth identity.Auth, id string) (transfer Transfer, err error) {
+
+	req := gorequest.New()
+
+	url := fmt.Sprintf("%s/os-volume-transfer/%s",
+		auth.EndpointList["volumev3"],
+		id)
+
+	resp, body, errs := req.Get(url).
+		Set("Content-Type", "application/json").
+		Set("Accept", "application/json").
+		Set("X-Auth-Token", auth.Access.Token.Id).
+		End()
+
+	if errs != nil {
+		err = errs[len(errs)-1]
+		return
+	}
+
+	if err = openstack.CheckHttpResponseStatusCode(resp.StatusCode); err != nil {
+		return
+	}
+
+	var r = transferResp{}
+	if err = json.Unmarshal([]byte(body), &r); err != nil {
+		return
+	}
+
+	transfer = r.Transfer
+	err = nil
+	return
+}
+
+// AcceptTransfer moves the volume into the project of auth.
+func AcceptTransfer(auth identity.Auth, id string, authKey string) (transfer Transfer, err error) {
+
+	req := gorequest.New()
+
+	url := fmt.Sprintf("%s/os-volume-transfer/%s/accept",
+		auth.EndpointList["volumev3"],
+		id)
+
+	var a acceptReq
+	a.Accept.AuthKey = authKey
+
+	b, err := json.Marshal(a)
+	if err != nil {
+		return
+	}
+
+	resp, body, errs := req.Post(url).
+		Set("Content-Type", "application/json").
+		Set("Accept", "application/json").
+		Set("X-Auth-Token", auth.Access.Token.Id).
+		Send(string(b)).
+		End()
+
+	if errs != nil {
+		err = errs[len(errs)-1]
+		return
+	}
+
+	if err = openstack.CheckHttpResponseStatusCode(resp.StatusCode); err != nil {
+		return
+	}
+
+	var r = transferResp{}
+	if err = json.Unmarshal([]byte(body), &r); err != nil {
+		return
+	}
+
+	transfer = r.Transfer
+	err = nil
+	return
+}
+
+func DeleteTransfer(auth identity.Auth, id string) (err error) {
+
+	req := gorequest.New()
+
+	url := fmt.Sprintf("%s/os-volume-transfer/%s",
+		auth.EndpointList["volumev3"],
+		id)
+
+	resp, _, errs := req.Delete(url).
+		Set("Content-Type", "application/json").
+		Set("Accept", "application/json").
+		Set("X-Auth-Token", auth.Access.Token.Id).
+		End()
+
+	if errs != nil {
+		err = errs[len(errs)-1]
+		return
+	}
+
+	if err = openstack.CheckHttpResponseStatusCode(resp.StatusCode); err != nil {
+		return
+	}
+
+	err = nil
+	return
+}
@@ -0,0 +1,372 @@
+// Package blockstorage is a client for the Cinder block storage v3 API. The
+// endpoint is the volumev3 entry of the service catalog, which includes the
+// project id.
+package blockstorage
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"strconv"
+
+	"github.com/gertd/go-openstack"
+	"github.com/gertd/go-openstack/identity"
+
+	"github.com/parnurzeal/gorequest"
+)
+
+type volumesResp struct {
+	Volumes []VolumeInfo     `json:"volumes"`
+	Links   []openstack.Link `json:"volumes_links"`
+}
+
+type volumesDetailResp struct {
+	Volumes []Volume         `json:"volumes"`
+	Links   []openstack.Link `json:"volumes_links"`
+}
+
+type volumeResp struct {
+	Volume Volume `json:"volume"`
+}
+
+type volumeReq struct {
+	Volume VolumeOpts `json:"volume"`
+}
+
+type VolumeInfo struct {
+	Id    string           `json:"id"`
+	Name  string           `json:"name"`
+	Links []openstack.Link `json:"links"`
+}
+
+// Bootable is returned by Cinder as the string "true" or "false".
+type Volume struct {
+	Id                 string             `json:"id"`
+	Name               string             `json:"name"`
+	Description        string             `json:"description"`
+	Status             string             `json:"status"`
+	Size               int                `json:"size"`
+	AvailabilityZone   string             `json:"availability_zone"`
+	VolumeType         string             `json:"volume_type"`
+	SnapshotId         string             `json:"snapshot_id"`
+	SourceVolId        string             `json:"source_volid"`
+	BackupId           string             `json:"backup_id"`
+	Bootable           string             `json:"bootable"`
+	Encrypted          bool               `json:"encrypted"`
+	Multiattach        bool               `json:"multiattach"`
+	ReplicationStatus  string             `json:"replication_status"`
+	MigrationStatus    string             `json:"migration_status"`
+	ConsistencyGroupId string             `json:"consistencygroup_id"`
+	GroupId            string             `json:"group_id"`
+	Metadata           map[string]string  `json:"metadata"`
+	ImageMetadata      map[string]string  `json:"volume_image_metadata"`
+	Attachments        []VolumeAttachment `json:"attachments"`
+	Host               string             `json:"os-vol-host-attr:host"`
+	TenantId           string             `json:"os-vol-tenant-attr:tenant_id"`
+	UserId             string             `json:"user_id"`
+	Links              []openstack.Link   `json:"links"`
+	CreatedAt          string             `json:"created_at"`
+	UpdatedAt          string             `json:"updated_at"`
+}
+
+type VolumeAttachment struct {
+	Id           string `json:"id"`
+	AttachmentId string `json:"attachment_id"`
+	VolumeId     string `json:"volume_id"`
+	ServerId     string `json:"server_id"`
+	HostName     string `json:"host_name"`
+	Device       string `json:"device"`
+	AttachedAt   string `json:"attached_at"`
+}
+
+// VolumeOpts is used to create and update volumes, nil and empty fields are
+// not sent. Only one of SnapshotId, SourceVolId, ImageRef and BackupId can be
+// set, and together with Size, VolumeType and AvailabilityZone only on
+// creation. Name, Description and Metadata can be updated.
+type VolumeOpts struct {
+	Size             int                `json:"size,omitempty"`
+	Name             *string            `json:"name,omitempty"`
+	Description      *string            `json:"description,omitempty"`
+	VolumeType       string             `json:"volume_type,omitempty"`
+	AvailabilityZone string             `json:"availability_zone,omitempty"`
+	SnapshotId       string             `json:"snapshot_id,omitempty"`
+	SourceVolId      string             `json:"source_volid,omitempty"`
+	ImageRef         string             `json:"imageRef,omitempty"`
+	BackupId         string             `json:"backup_id,omitempty"`
+	Multiattach      bool               `json:"multiattach,omitempty"`
+	Metadata         *map[string]string `json:"metadata,omitempty"`
+}
+
+// VolumeFilter narrows GetVolumesDetail, empty fields are not sent.
+// AllTenants requires admin rights.
+type VolumeFilter struct {
+	Name       string
+	Status     string
+	Bootable   *bool
+	Metadata   map[string]string
+	AllTenants bool
+}
+
+type ByName []VolumeInfo
+
+func (a ByName) Len() int           { return len(a) }
+func (a ByName) Swap(i, j int)      { a[i], a[j] = a[j], a[i] }
+func (a ByName) Less(i, j int) bool { return a[i].Name < a[j].Name }
+
+func GetVolumes(auth identity.Auth) (volumes []VolumeInfo, err error) {
+
+	reqUrl := fmt.Sprintf("%s/volumes",
+		auth.EndpointList["volumev3"])
+
+	for len(reqUrl) > 0 {
+		req := gorequest.New()
+
+		resp, body, errs := req.Get(reqUrl).
+			Set("Content-Type", "application/json").
+			Set("Accept", "application/json").
+			Set("X-Auth-Token", auth.Access.Token.Id).
+			End()
+
+		if errs != nil {
+			err = errs[len(errs)-1]
+			return
+		}
+
+		if err = openstack.CheckHttpResponseStatusCode(resp.StatusCode); err != nil {
+			return
+		}
+
+		var r = volumesResp{}
+		if err = json.Unmarshal([]byte(body), &r); err != nil {
+			return
+		}
+
+		volumes = append(volumes, r.Volumes...)
+
+		reqUrl = nextLink(r.Links)
+	}
+
+	err = nil
+	return
+}
+
+func GetVolumesDetail(auth identity.Auth, filter VolumeFilter) (volumes []Volume, err error) {
+
+	query := url.Values{}
+	if len(filter.Name) > 0 {
+		query.Set("name", filter.Name)
+	}
+	if len(filter.Status) > 0 {
+		query.Set("status", filter.Status)
+	}
+	if filter.Bootable != nil {
+		query.Set("bootable", strconv.FormatBool(*filter.Bootable))
+	}
+	if len(filter.Metadata) > 0 {
+		b, e := json.Marshal(filter.Metadata)
+		if e != nil {
+			err = e
+			return
+		}
+		query.Set("metadata", string(b))
+	}
+	if filter.AllTenants {
+		query.Set("all_tenants", "1")
+	}
+
+	reqUrl := fmt.Sprintf("%s/volumes/detail?%s",
+		auth.EndpointList["volumev3"],
+		query.Encode())
+
+	for len(reqUrl) > 0 {
+		req := gorequest.New()
+
+		resp, body, errs := req.Get(reqUrl).
+			Set("Content-Type", "application/json").
+			Set("Accept", "application/json").
+			Set("X-Auth-Token", auth.Access.Token.Id).
+			End()
+
+		if errs != nil {
+			err = errs[len(errs)-1]
+			return
+		}
+
+		if err = openstack.CheckHttpResponseStatusCode(resp.StatusCode); err != nil {
+			return
+		}
+
+		var r = volumesDetailResp{}
+		if err = json.Unmarshal([]byte(body), &r); err != nil {
+			return
+		}
+
+		volumes = append(volumes, r.Volumes...)
+
+		reqUrl = nextLink(r.Links)
+	}
+
+	err = nil
+	return
+}
+
+func GetVolume(auth identity.Auth, id string) (volume Volume, err error) {
+
+	req := gorequest.New()
+
+	url := fmt.Sprintf("%s/volumes/%s",
+		auth.EndpointList["volumev3"],
+		id)
+
+	resp, body, errs := req.Get(url).
+		Set("Content-Type", "application/json").
+		Set("Accept", "application/json").
+		Set("X-Auth-Token", auth.Access.Token.Id).
+		End()
+
+	if errs != nil {
+		err = errs[len(errs)-1]
+		return
+	}
+
+	if err = openstack.CheckHttpResponseStatusCode(resp.StatusCode); err != nil {
+		return
+	}
+
+	var r = volumeResp{}
+	if err = json.Unmarshal([]byte(body), &r); err != nil {
+		return
+	}
+
+	volume = r.Volume
+	err = nil
+	return
+}
+
+// CreateVolume creates a volume, the volume is usable once its status is
+// available, see WaitForVolumeStatus. Creating from a backup requires block
+// storage API microversion 3.47.
+func CreateVolume(auth identity.Auth, opts VolumeOpts) (volume Volume, err error) {
+
+	req := gorequest.New()
+
+	url := fmt.Sprintf("%s/volumes",
+		auth.EndpointList["volumev3"])
+
+	b, err := json.Marshal(volumeReq{opts})
+	if err != nil {
+		return
+	}
+
+	req.Post(url).
+		Set("Content-Type", "application/json").
+		Set("Accept", "application/json").
+		Set("X-Auth-Token", auth.Access.Token.Id)
+
+	if len(opts.BackupId) > 0 {
+		req.Set("OpenStack-API-Version", "volume 3.47")
+	}
+
+	resp, body, errs := req.Send(string(b)).End()
+
+	if errs != nil {
+		err = errs[len(errs)-1]
+		return
+	}
+
+	if err = openstack.CheckHttpResponseStatusCode(resp.StatusCode); err != nil {
+		return
+	}
+
+	var r = volumeResp{}
+	if err = json.Unmarshal([]byte(body), &r); err != nil {
+		return
+	}
+
+	volume = r.Volume
+	err = nil
+	return
+}
+
+func UpdateVolume(auth identity.Auth, id string, opts VolumeOpts) (volume Volume, err error) {
+
+	req := gorequest.New()
+
+	url := fmt.Sprintf("%s/volumes/%s",
+		auth.EndpointList["volumev3"],
+		id)
+
+	b, err := json.Marshal(volumeReq{opts})
+	if err != nil {
+		return
+	}
+
+	resp, body, errs := req.Put(url).
+		Set("Content-Type", "application/json").
+		Set("Accept", "application/json").
+		Set("X-Auth-Token", auth.Access.Token.Id).
+		Send(string(b)).
+		End()
+
+	if errs != nil {
+		err = errs[len(errs)-1]
+		return
+	}
+
+	if err = openstack.CheckHttpResponseStatusCode(resp.StatusCode); err != nil {
+		return
+	}
+
+	var r = volumeResp{}
+	if err = json.Unmarshal([]byte(body), &r); err != nil {
+		return
+	}
+
+	volume = r.Volume
+	err = nil
+	return
+}
+
+// DeleteVolume deletes the volume, cascade also deletes its snapshots.
+func DeleteVolume(auth identity.Auth, id string, cascade bool) (err error) {
+
+	req := gorequest.New()
+
+	url := fmt.Sprintf("%s/volumes/%s",
+		auth.EndpointList["volumev3"],
+		id)
+	if cascade {
+		url += "?cascade=true"
+	}
+
+	resp, _, errs := req.Delete(url).
+		Set("Content-Type", "application/json").
+		Set("Accept", "application/json").
+		Set("X-Auth-Token", auth.Access.Token.Id).
+		End()
+
+	if errs != nil {
+		err = errs[len(errs)-1]
+		return
+	}
+
+	if err = openstack.CheckHttpResponseStatusCode(resp.StatusCode); err != nil {
+		return
+	}
+
+	err = nil
+	return
+}
+
+// nextLink returns the href of the next page of a listing, Cinder pages
+// listings at osapi_max_limit entries. It is empty on the last page.
+func nextLink(links []openstack.Link) string {
+
+	for _, v := range links {
+		if v.Rel == "next" {
+			return v.HRef
+		}
+	}
+
+	return ""
+}
@@ -0,0 +1,457 @@
+package blockstorage
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/gertd/go-openstack"
+	"github.com/gertd/go-openstack/identity"
+
+	"github.com/parnurzeal/gorequest"
+)
+
+type volumeTypesResp struct {
+	VolumeTypes []VolumeType `json:"volume_types"`
+}
+
+type volumeTypeResp struct {
+	VolumeType VolumeType `json:"volume_type"`
+}
+
+type volumeTypeReq struct {
+	VolumeType VolumeTypeOpts `json:"volume_type"`
+}
+
+type extraSpecsResp struct {
+	ExtraSpecs map[string]string `json:"extra_specs"`
+}
+
+type extraSpecsReq struct {
+	ExtraSpecs map[string]string `json:"extra_specs"`
+}
+
+type typeAccessResp struct {
+	VolumeTypeAccess []VolumeTypeAccess `json:"volume_type_access"`
+}
+
+type addProjectAccessReq struct {
+	AddProjectAccess projectRef `json:"addProjectAccess"`
+}
+
+type removeProjectAccessReq struct {
+	RemoveProjectAccess projectRef `json:"removeProjectAccess"`
+}
+
+type projectRef struct {
+	Project string `json:"project"`
+}
+
+// ExtraSpecs are the scheduler and backend hints of the type, such as
+// volume_backend_name, they are only returned to admins.
+type VolumeType struct {
+	Id          string            `json:"id"`
+	Name        string            `json:"name"`
+	Description string            `json:"description"`
+	IsPublic    bool              `json:"os-volume-type-access:is_public"`
+	QoSSpecsId  string            `json:"qos_specs_id"`
+	ExtraSpecs  map[string]string `json:"extra_specs"`
+}
+
+// VolumeTypeOpts is used to create and update volume types, nil and empty
+// fields are not sent. ExtraSpecs is only used on creation, use
+// SetVolumeTypeExtraSpecs afterwards. Private types are only visible to the
+// projects granted access with AddVolumeTypeAccess.
+type VolumeTypeOpts struct {
+	Name        string            `json:"name,omitempty"`
+	Description *string           `json:"description,omitempty"`
+	IsPublic    *bool             `json:"os-volume-type-access:is_public,omitempty"`
+	ExtraSpecs  map[string]string `json:"extra_specs,omitempty"`
+}
+
+type VolumeTypeAccess struct {
+	VolumeTypeId string `json:"volume_type_id"`
+	ProjectId    string `json:"project_id"`
+}
+
+func GetVolumeTypes(auth identity.Auth) (volumeTypes []VolumeType, err error) {
+
+	req := gorequest.New()
+
+	url := fmt.Sprintf("%s/types",
+		auth.EndpointList["volumev3"])
+
+	resp, body, errs := req.Get(url).
+		Set("Content-Type", "application/json").
+		Set("Accept", "application/json").
+		Set("X-Auth-Token", auth.Access.Token.Id).
+		End()
+
+	if errs != nil {
+		err = errs[len(errs)-1]
+		return
+	}
+
+	if err = openstack.CheckHttpResponseStatusCode(resp.StatusCode); err != nil {
+		return
+	}
+
+	var r = volumeTypesResp{}
+	if err = json.Unmarshal([]byte(body), &r); err != nil {
+		return
+	}
+
+	volumeTypes = r.VolumeTypes
+	err = nil
+	return
+}
+
+func GetVolumeType(auth identity.Auth, id string) (volumeType VolumeType, err error) {
+
+	req := gorequest.New()
+
+	url := fmt.Sprintf("%s/types/%s",
+		auth.EndpointList["volumev3"],
+		id)
+
+	resp, body, errs := req.Get(url).
+		Set("Content-Type", "application/json").
+		Set("Accept", "application/json").
+		Set("X-Auth-Token", auth.Access.Token.Id).
+		End()
+
+	if errs != nil {
+		err = errs[len(errs)-1]
+		return
+	}
+
+	if err = openstack.CheckHttpResponseStatusCode(resp.StatusCode); err != nil {
+		return
+	}
+
+	var r = volumeTypeResp{}
+	if err = json.Unmarshal([]byte(body), &r); err != nil {
+		return
+	}
+
+	volumeType = r.VolumeType
+	err = nil
+	return
+}
+
+func CreateVolumeType(auth identity.Auth, opts VolumeTypeOpts) (volumeType VolumeType, err error) {
+
+	req := gorequest.New()
+
+	url := fmt.Sprintf("%s/types",
+		auth.EndpointList["volumev3"])
+
+	b, err := json.Marshal(volumeTypeReq{opts})
+	if err != nil {
+		return
+	}
+
+	resp, body, errs := req.Post(url).
+		Set("Content-Type", "application/json").
+		Set("Accept", "application/json").
+		Set("X-Auth-Token", auth.Access.Token.Id).
+		Send(string(b)).
+		End()
+
+	if errs != nil {
+		err = errs[len(errs)-1]
+		return
+	}
+
+	if err = openstack.CheckHttpResponseStatusCode(resp.StatusCode); err != nil {
+		return
+	}
+
+	var r = volumeTypeResp{}
+	if err = json.Unmarshal([]byte(body), &r); err != nil {
+		return
+	}
+
+	volumeType = r.VolumeType
+	err = nil
+	return
+}
+
+func UpdateVolumeType(auth identity.Auth, id string, opts VolumeTypeOpts) (volumeType VolumeType, err error) {
+
+	req := gorequest.New()
+
+	url := fmt.Sprintf("%s/types/%s",
+		auth.EndpointList["volumev3"],
+		id)
+
+	var update struct {
+		VolumeType struct {
+			Name        string  `json:"name,omitempty"`
+			Description *string `json:"description,omitempty"`
+			IsPublic    *bool   `json:"is_public,omitempty"`
+		} `json:"volume_type"`
+	}
+	update.VolumeType.Name = opts.Name
+	update.VolumeType.Description = opts.Description
+	update.VolumeType.IsPublic = opts.IsPublic
+
+	b, err := json.Marshal(update)
+	if err != nil {
+		return
+	}
+
+	resp, body, errs := req.Put(url).
+		Set("Content-Type", "application/json").
+		Set("Accept", "application/json").
+		Set("X-Auth-Token", auth.Access.Token.Id).
+		Send(string(b)).
+		End()
+
+	if errs != nil {
+		err = errs[len(errs)-1]
+		return
+	}
+
+	if err = openstack.CheckHttpResponseStatusCode(resp.StatusCode); err != nil {
+		return
+	}
+
+	var r = volumeTypeResp{}
+	if err = json.Unmarshal([]byte(body), &r); err != nil {
+		return
+	}
+
+	volumeType = r.VolumeType
+	err = nil
+	return
+}
+
+func DeleteVolumeType(auth identity.Auth, id string) (err error) {
+
+	req := gorequest.New()
+
+	url := fmt.Sprintf("%s/types/%s",
+		auth.EndpointList["volumev3"],
+		id)
+
+	resp, _, errs := req.Delete(url).
+		Set("Content-Type", "application/json").
+		Set("Accept", "application/json").
+		Set("X-Auth-Token", auth.Access.Token.Id).
+		End()
+
+	if errs != nil {
+		err = errs[len(errs)-1]
+		return
+	}
+
+	if err = openstack.CheckHttpResponseStatusCode(resp.StatusCode); err != nil {
+		return
+	}
+
+	err = nil
+	return
+}
+
+func GetVolumeTypeExtraSpecs(auth identity.Auth, id string) (extraSpecs map[string]string, err error) {
+
+	req := gorequest.New()
+
+	url := fmt.Sprintf("%s/types/%s/extra_specs",
+		auth.EndpointList["volumev3"],
+		id)
+
+	resp, body, errs := req.Get(url).
+		Set("Content-Type", "application/json").
+		Set("Accept", "application/json").
+		Set("X-Auth-Token", auth.Access.Token.Id).
+		End()
+
+	if errs != nil {
+		err = errs[len(errs)-1]
+		return
+	}
+
+	if err = openstack.CheckHttpResponseStatusCode(resp.StatusCode); err != nil {
+		return
+	}
+
+	var r = extraSpecsResp{}
+	if err = json.Unmarshal([]byte(body), &r); err != nil {
+		return
+	}
+
+	extraSpecs = r.ExtraSpecs
+	err = nil
+	return
+}
+
+// SetVolumeTypeExtraSpecs adds extraSpecs to the volume type, existing keys
+// are overwritten and the others kept.
+func SetVolumeTypeExtraSpecs(auth identity.Auth, id string, extraSpecs map[string]string) (result map[string]string, err error) {
+
+	req := gorequest.New()
+
+	url := fmt.Sprintf("%s/types/%s/extra_specs",
+		auth.EndpointList["volumev3"],
+		id)
+
+	b, err := json.Marshal(extraSpecsReq{extraSpecs})
+	if err != nil {
+		return
+	}
+
+	resp, body, errs := req.Post(url).
+		Set("Content-Type", "application/json").
+		Set("Accept", "application/json").
+		Set("X-Auth-Token", auth.Access.Token.Id).
+		Send(string(b)).
+		End()
+
+	if errs != nil {
+		err = errs[len(errs)-1]
+		return
+	}
+
+	if err = openstack.CheckHttpResponseStatusCode(resp.StatusCode); err != nil {
+		return
+	}
+
+	var r = extraSpecsResp{}
+	if err = json.Unmarshal([]byte(body), &r); err != nil {
+		return
+	}
+
+	result = r.ExtraSpecs
+	err = nil
+	return
+}
+
+func DeleteVolumeTypeExtraSpec(auth identity.Auth, id string, key string) (err error) {
+
+	req := gorequest.New()
+
+	url := fmt.Sprintf("%s/types/%s/extra_specs/%s",
+		auth.EndpointList["volumev3"],
+		id,
+		key)
+
+	resp, _, errs := req.Delete(url).
+		Set("Content-Type", "application/json").
+		Set("Accept", "application/json").
+		Set("X-Auth-Token", auth.Access.Token.Id).
+		End()
+
+	if errs != nil {
+		err = errs[len(errs)-1]
+		return
+	}
+
+	if err = openstack.CheckHttpResponseStatusCode(resp.StatusCode); err != nil {
+		return
+	}
+
+	err = nil
+	return
+}
+
+// GetVolumeTypeAccess lists the projects granted access to a private volume
+// type.
+func GetVolumeTypeAccess(auth identity.Auth, id string) (access []VolumeTypeAccess, err error) {
+
+	req := gorequest.New()
+
+	url := fmt.Sprintf("%s/types/%s/os-volume-type-access",
+		auth.EndpointList["volumev3"],
+		id)
+
+	resp, body, errs := req.Get(url).
+		Set("Content-Type", "application/json").
+		Set("Accept", "application/json").
+		Set("X-Auth-Token", auth.Access.Token.Id).
+		End()
+
+	if errs != nil {
+		err = errs[len(errs)-1]
+		return
+	}
+
+	if err = openstack.CheckHttpResponseStatusCode(resp.StatusCode); err != nil {
+		return
+	}
+
+	var r = typeAccessResp{}
+	if err = json.Unmarshal([]byte(body), &r); err != nil {
+		return
+	}
+
+	access = r.VolumeTypeAccess
+	err = nil
+	return
+}
+
+func AddVolumeTypeAccess(auth identity.Auth, id string, projectId string) (err error) {
+
+	req := gorequest.New()
+
+	url := fmt.Sprintf("%s/types/%s/action",
+		auth.EndpointList["volumev3"],
+		id)
+
+	b, err := json.Marshal(addProjectAccessReq{projectRef{projectId}})
+	if err != nil {
+		return
+	}
+
+	resp, _, errs := req.Post(url).
+		Set("Content-Type", "application/json").
+		Set("Accept", "application/json").
+		Set("X-Auth-Token", auth.Access.Token.Id).
+		Send(string(b)).
+		End()
+
+	if errs != nil {
+		err = errs[len(errs)-1]
+		return
+	}
+
+	if err = openstack.CheckHttpResponseStatusCode(resp.StatusCode); err != nil {
+		return
+	}
+
+	err = nil
+	return
+}
+
+func RemoveVolumeTypeAccess(auth identity.Auth, id string, projectId string) (err error) {
+
+	req := gorequest.New()
+
+	url := fmt.Sprintf("%s/types/%s/action",
+		auth.EndpointList["volumev3"],
+		id)
+
+	b, err := json.Marshal(removeProjectAccessReq{projectRef{projectId}})
+	if err != nil {
+		return
+	}
+
+	resp, _, errs := req.Post(url).
+		Set("Content-Type", "application/json").
+		Set("Accept", "application/json").
+		Set("X-Auth-Token", auth.Access.Token.Id).
+		Send(string(b)).
+		End()
+
+	if errs != nil {
+		err = errs[len(errs)-1]
+		return
+	}
+
+	if err = openstack.CheckHttpResponseStatusCode(resp.StatusCode); err != nil {
+		return
+	}
+
+	err = nil
+	return
+}
@@ -0,0 +1,134 @@
+package blockstorage
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/gertd/go-openstack"
+	"github.com/gertd/go-openstack/identity"
+
+	"github.com/parnurzeal/gorequest"
+)
+
+const pollInterval = 5 * time.Second
+
+// WaitForVolumeStatus polls the volume until it reaches status, typically
+// available or in-use. An error is returned when the volume goes to one of
+// the error statuses or the timeout expires, a timeout of zero waits
+// forever.
+func WaitForVolumeStatus(auth identity.Auth, id string, status string, timeout time.Duration) (volume Volume, err error) {
+
+	err = waitForStatus("volume", id, status, timeout, func() (string, error) {
+		var e error
+		volume, e = GetVolume(auth, id)
+		return volume.Status, e
+	})
+	return
+}
+
+func WaitForSnapshotStatus(auth identity.Auth, id string, status string, timeout time.Duration) (snapshot Snapshot, err error) {
+
+	err = waitForStatus("snapshot", id, status, timeout, func() (string, error) {
+		var e error
+		snapshot, e = GetSnapshot(auth, id)
+		return snapshot.Status, e
+	})
+	return
+}
+
+func WaitForBackupStatus(auth identity.Auth, id string, status string, timeout time.Duration) (backup Backup, err error) {
+
+	err = waitForStatus("backup", id, status, timeout, func() (string, error) {
+		var e error
+		backup, e = GetBackup(auth, id)
+		return backup.Status, e
+	})
+	return
+}
+
+// WaitForVolumeDeleted polls the volume until it is gone. A timeout of zero
+// waits forever.
+func WaitForVolumeDeleted(auth identity.Auth, id string, timeout time.Duration) (err error) {
+
+	url := fmt.Sprintf("%s/volumes/%s",
+		auth.EndpointList["volumev3"],
+		id)
+
+	return waitForDeleted(auth, "volume", id, url, timeout)
+}
+
+func WaitForBackupDeleted(auth identity.Auth, id string, timeout time.Duration) (err error) {
+
+	url := fmt.Sprintf("%s/backups/%s",
+		auth.EndpointList["volumev3"],
+		id)
+
+	return waitForDeleted(auth, "backup", id, url, timeout)
+}
+
+func waitForStatus(kind string, id string, status string, timeout time.Duration, get func() (string, error)) (err error) {
+
+	start := time.Now()
+
+	for {
+		var s string
+		if s, err = get(); err != nil {
+			return
+		}
+
+		if s == status {
+			err = nil
+			return
+		}
+
+		if strings.HasPrefix(s, "error") {
+			err = errors.New(fmt.Sprintf("%s %s is %s", kind, id, s))
+			return
+		}
+
+		if timeout > 0 && time.Since(start) > timeout {
+			err = errors.New(fmt.Sprintf("timeout waiting for %s %s to become %s (status %s)", kind, id, status, s))
+			return
+		}
+
+		time.Sleep(pollInterval)
+	}
+}
+
+func waitForDeleted(auth identity.Auth, kind string, id string, url string, timeout time.Duration) (err error) {
+
+	start := time.Now()
+
+	for {
+		req := gorequest.New()
+
+		resp, _, errs := req.Get(url).
+			Set("Content-Type", "application/json").
+			Set("Accept", "application/json").
+			Set("X-Auth-Token", auth.Access.Token.Id).
+			End()
+
+		if errs != nil {
+			err = errs[len(errs)-1]
+			return
+		}
+
+		if resp.StatusCode == 404 {
+			err = nil
+			return
+		}
+
+		if err = openstack.CheckHttpResponseStatusCode(resp.StatusCode); err != nil {
+			return
+		}
+
+		if timeout > 0 && time.Since(start) > timeout {
+			err = errors.New(fmt.Sprintf("timeout waiting for %s %s to be deleted", kind, id))
+			return
+		}
+
+		time.Sleep(pollInterval)
+	}
+}
@@ -0,0 +1,97 @@
+package compute
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/gertd/go-openstack"
+	"github.com/gertd/go-openstack/identity"
+
+	"github.com/parnurzeal/gorequest"
+)
+
+type availabilityZonesResp struct {
+	AvailabilityZoneInfo []AvailabilityZone `json:"availabilityZoneInfo"`
+}
+
+// Hosts is only populated by GetAvailabilityZonesDetail and maps a host
+// name to the state of each service running on it.
+type AvailabilityZone struct {
+	ZoneName  string                                    `json:"zoneName"`
+	ZoneState ZoneState                                 `json:"zoneState"`
+	Hosts     map[string]map[string]AvailabilityZoneSvc `json:"hosts"`
+}
+
+type ZoneState struct {
+	Available bool `json:"available"`
+}
+
+type AvailabilityZoneSvc struct {
+	Available bool        `json:"available"`
+	Active    bool        `json:"active"`
+	UpdatedAt interface{} `json:"updated_at"`
+}
+
+func GetAvailabilityZones(auth identity.Auth) (zones []AvailabilityZone, err error) {
+
+	req := gorequest.New()
+
+	url := fmt.Sprintf("%s/os-availability-zone",
+		auth.EndpointList["compute"])
+
+	resp, body, errs := req.Get(url).
+		Set("Content-Type", "application/json").
+		Set("Accept", "application/json").
+		Set("X-Auth-Token", auth.Access.Token.Id).
+		End()
+
+	if errs != nil {
+		err = errs[len(errs)-1]
+		return
+	}
+
+	if err = openstack.CheckHttpResponseStatusCode(resp.StatusCode); err != nil {
+		return
+	}
+
+	var r = availabilityZonesResp{}
+	if err = json.Unmarshal([]byte(body), &r); err != nil {
+		return
+	}
+
+	zones = r.AvailabilityZoneInfo
+	err = nil
+	return
+}
+
+func GetAvailabilityZonesDetail(auth identity.Auth) (zones []AvailabilityZone, err error) {
+
+	req := gorequest.New()
+
+	url := fmt.Sprintf("%s/os-availability-zone/detail",
+		auth.EndpointList["compute"])
+
+	resp, body, errs := req.Get(url).
+		Set("Content-Type", "application/json").
+		Set("Accept", "application/json").
+		Set("X-Auth-Token", auth.Access.Token.Id).
+		End()
+
+	if errs != nil {
+		err = errs[len(errs)-1]
+		return
+	}
+
+	if err = openstack.CheckHttpResponseStatusCode(resp.StatusCode); err != nil {
+		return
+	}
+
+	var r = availabilityZonesResp{}
+	if err = json.Unmarshal([]byte(body), &r); err != nil {
+		return
+	}
+
+	zones = r.AvailabilityZoneInfo
+	err = nil
+	return
+}
@@ -0,0 +1,153 @@
+package compute
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/gertd/go-openstack/identity"
+)
+
+const drainPollInterval = 5 * time.Second
+
+// DrainOpts controls DrainHost. Live selects live migration rather than cold
+// migration, BlockMigration is passed through to LiveMigrateServer,
+// Concurrency bounds the number of servers migrated at the same time and
+// Timeout bounds the time spent waiting for a single server.
+type DrainOpts struct {
+	Live           bool
+	BlockMigration *bool
+	Concurrency    int
+	Timeout        time.Duration
+}
+
+// DrainResult is the outcome of moving a single server. Err is nil when the
+// server ended up on DestHost.
+type DrainResult struct {
+	ServerId   string
+	Name       string
+	SourceHost string
+	DestHost   string
+	Err        error
+}
+
+// DrainHost migrates every server off the hypervisor and reports the
+// outcome for each server, in the order they were listed by the hypervisor.
+// Cold migrations are confirmed once they reach VERIFY_RESIZE.
+func DrainHost(auth identity.Auth, hypervisorHostname string, opts DrainOpts) (results []DrainResult, err error) {
+
+	hypervisors, err := GetHypervisorServers(auth, hypervisorHostname)
+	if err != nil {
+		return
+	}
+
+	var servers []HypervisorServer
+	for _, v := range hypervisors {
+		if v.HypervisorHostname == hypervisorHostname {
+			servers = append(servers, v.Servers...)
+		}
+	}
+
+	concurrency := opts.Concurrency
+	if concurrency < 1 {
+		concurrency = 1
+	}
+
+	results = make([]DrainResult, len(servers))
+	sem := make(chan struct{}, concurrency)
+	var wg sync.WaitGroup
+
+	for i, v := range servers {
+		wg.Add(1)
+		sem <- struct{}{}
+		go func(i int, s HypervisorServer) {
+			defer wg.Done()
+			results[i] = drainServer(auth, s, opts)
+			<-sem
+		}(i, v)
+	}
+
+	wg.Wait()
+
+	err = nil
+	return
+}
+
+func drainServer(auth identity.Auth, s HypervisorServer, opts DrainOpts) (result DrainResult) {
+
+	result = DrainResult{ServerId: s.Uuid, Name: s.Name}
+
+	server, err := GetServer(auth, s.Uuid)
+	if err != nil {
+		result.Err = err
+		return
+	}
+	result.SourceHost = server.Host
+
+	if opts.Live {
+		err = LiveMigrateServer(auth, s.Uuid, LiveMigrateOpts{BlockMigration: opts.BlockMigration})
+	} else {
+		err = MigrateServer(auth, s.Uuid, "")
+	}
+	if err != nil {
+		result.Err = err
+		return
+	}
+
+	server, err = waitForMigration(auth, s.Uuid, opts.Timeout)
+	if err != nil {
+		result.Err = err
+		return
+	}
+
+	if server.Status == "VERIFY_RESIZE" {
+		if err = ConfirmResizeServer(auth, s.Uuid); err != nil {
+			result.Err = err
+			return
+		}
+		if server, err = waitForMigration(auth, s.Uuid, opts.Timeout); err != nil {
+			result.Err = err
+			return
+		}
+	}
+
+	result.DestHost = server.Host
+
+	if server.Status == "ERROR" {
+		result.Err = errors.New(fmt.Sprintf("server %s is in ERROR state after migration", s.Uuid))
+		return
+	}
+	if server.Host == result.SourceHost {
+		result.Err = errors.New(fmt.Sprintf("server %s is still on host %s", s.Uuid, server.Host))
+		return
+	}
+
+	return
+}
+
+// waitForMigration polls the server until no task is in progress, a
+// timeout of zero waits forever.
+func waitForMigration(auth identity.Auth, id string, timeout time.Duration) (server Server, err error) {
+
+	start := time.Now()
+
+	for {
+		time.Sleep(drainPollInterval)
+
+		server, err = GetServer(auth, id)
+		if err != nil {
+			return
+		}
+
+		if len(server.TaskState) == 0 && server.Status != "MIGRATING" && server.Status != "RESIZE" {
+			err = nil
+			return
+		}
+
+		if timeout > 0 && time.Since(start) > timeout {
+			err = errors.New(fmt.Sprintf("timeout waiting for server %s to migrate (status %s, task %s)", id, server.Status, server.TaskState))
+			return
+		}
+	}
+}
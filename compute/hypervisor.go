@@ -0,0 +1,222 @@
+package compute
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/gertd/go-openstack"
+	"github.com/gertd/go-openstack/identity"
+
+	"github.com/parnurzeal/gorequest"
+)
+
+type hypervisorsResp struct {
+	Hypervisors []Hypervisor `json:"hypervisors"`
+}
+
+type hypervisorsDetailResp struct {
+	Hypervisors []HypervisorDetail `json:"hypervisors"`
+}
+
+type hypervisorServersResp struct {
+	Hypervisors []HypervisorServers `json:"hypervisors"`
+}
+
+type hypervisorStatisticsResp struct {
+	HypervisorStatistics HypervisorStatistics `json:"hypervisor_statistics"`
+}
+
+type Hypervisor struct {
+	Id                 interface{} `json:"id"`
+	HypervisorHostname string      `json:"hypervisor_hostname"`
+	State              string      `json:"state"`
+	Status             string      `json:"status"`
+}
+
+type HypervisorDetail struct {
+	Id                 interface{}       `json:"id"`
+	HypervisorHostname string            `json:"hypervisor_hostname"`
+	State              string            `json:"state"`
+	Status             string            `json:"status"`
+	HypervisorType     string            `json:"hypervisor_type"`
+	HypervisorVersion  int               `json:"hypervisor_version"`
+	HostIP             string            `json:"host_ip"`
+	CPUInfo            interface{}       `json:"cpu_info"`
+	CurrentWorkload    int               `json:"current_workload"`
+	RunningVMs         int               `json:"running_vms"`
+	VCPUs              int               `json:"vcpus"`
+	VCPUsUsed          int               `json:"vcpus_used"`
+	MemoryMB           int               `json:"memory_mb"`
+	MemoryMBUsed       int               `json:"memory_mb_used"`
+	FreeRamMB          int               `json:"free_ram_mb"`
+	LocalGB            int               `json:"local_gb"`
+	LocalGBUsed        int               `json:"local_gb_used"`
+	FreeDiskGB         int               `json:"free_disk_gb"`
+	DiskAvailableLeast int               `json:"disk_available_least"`
+	Service            HypervisorService `json:"service"`
+}
+
+type HypervisorService struct {
+	Id             interface{} `json:"id"`
+	Host           string      `json:"host"`
+	DisabledReason string      `json:"disabled_reason"`
+}
+
+type HypervisorStatistics struct {
+	Count              int `json:"count"`
+	CurrentWorkload    int `json:"current_workload"`
+	RunningVMs         int `json:"running_vms"`
+	VCPUs              int `json:"vcpus"`
+	VCPUsUsed          int `json:"vcpus_used"`
+	MemoryMB           int `json:"memory_mb"`
+	MemoryMBUsed       int `json:"memory_mb_used"`
+	FreeRamMB          int `json:"free_ram_mb"`
+	LocalGB            int `json:"local_gb"`
+	LocalGBUsed        int `json:"local_gb_used"`
+	FreeDiskGB         int `json:"free_disk_gb"`
+	DiskAvailableLeast int `json:"disk_available_least"`
+}
+
+type HypervisorServers struct {
+	Id                 interface{}        `json:"id"`
+	HypervisorHostname string             `json:"hypervisor_hostname"`
+	State              string             `json:"state"`
+	Status             string             `json:"status"`
+	Servers            []HypervisorServer `json:"servers"`
+}
+
+type HypervisorServer struct {
+	Name string `json:"name"`
+	Uuid string `json:"uuid"`
+}
+
+func GetHypervisors(auth identity.Auth) (hypervisors []Hypervisor, err error) {
+
+	req := gorequest.New()
+
+	url := fmt.Sprintf("%s/os-hypervisors",
+		auth.EndpointList["compute"])
+
+	resp, body, errs := req.Get(url).
+		Set("Content-Type", "application/json").
+		Set("Accept", "application/json").
+		Set("X-Auth-Token", auth.Access.Token.Id).
+		End()
+
+	if errs != nil {
+		err = errs[len(errs)-1]
+		return
+	}
+
+	if err = openstack.CheckHttpResponseStatusCode(resp.StatusCode); err != nil {
+		return
+	}
+
+	var r = hypervisorsResp{}
+	if err = json.Unmarshal([]byte(body), &r); err != nil {
+		return
+	}
+
+	hypervisors = r.Hypervisors
+	err = nil
+	return
+}
+
+func GetHypervisorsDetail(auth identity.Auth) (hypervisors []HypervisorDetail, err error) {
+
+	req := gorequest.New()
+
+	url := fmt.Sprintf("%s/os-hypervisors/detail",
+		auth.EndpointList["compute"])
+
+	resp, body, errs := req.Get(url).
+		Set("Content-Type", "application/json").
+		Set("Accept", "application/json").
+		Set("X-Auth-Token", auth.Access.Token.Id).
+		End()
+
+	if errs != nil {
+		err = errs[len(errs)-1]
+		return
+	}
+
+	if err = openstack.CheckHttpResponseStatusCode(resp.StatusCode); err != nil {
+		return
+	}
+
+	var r = hypervisorsDetailResp{}
+	if err = json.Unmarshal([]byte(body), &r); err != nil {
+		return
+	}
+
+	hypervisors = r.Hypervisors
+	err = nil
+	return
+}
+
+func GetHypervisorStatistics(auth identity.Auth) (statistics HypervisorStatistics, err error) {
+
+	req := gorequest.New()
+
+	url := fmt.Sprintf("%s/os-hypervisors/statistics",
+		auth.EndpointList["compute"])
+
+	resp, body, errs := req.Get(url).
+		Set("Content-Type", "application/json").
+		Set("Accept", "application/json").
+		Set("X-Auth-Token", auth.Access.Token.Id).
+		End()
+
+	if errs != nil {
+		err = errs[len(errs)-1]
+		return
+	}
+
+	if err = openstack.CheckHttpResponseStatusCode(resp.StatusCode); err != nil {
+		return
+	}
+
+	var r = hypervisorStatisticsResp{}
+	if err = json.Unmarshal([]byte(body), &r); err != nil {
+		return
+	}
+
+	statistics = r.HypervisorStatistics
+	err = nil
+	return
+}
+
+// GetHypervisorServers returns the servers running on every hypervisor
+// whose hostname matches hostnamePattern.
+func GetHypervisorServers(auth identity.Auth, hostnamePattern string) (hypervisors []HypervisorServers, err error) {
+
+	req := gorequest.New()
+
+	url := fmt.Sprintf("%s/os-hypervisors/%s/servers",
+		auth.EndpointList["compute"],
+		hostnamePattern)
+
+	resp, body, errs := req.Get(url).
+		Set("Content-Type", "application/json").
+		Set("Accept", "application/json").
+		Set("X-Auth-Token", auth.Access.Token.Id).
+		End()
+
+	if errs != nil {
+		err = errs[len(errs)-1]
+		return
+	}
+
+	if err = openstack.CheckHttpResponseStatusCode(resp.StatusCode); err != nil {
+		return
+	}
+
+	var r = hypervisorServersResp{}
+	if err = json.Unmarshal([]byte(body), &r); err != nil {
+		return
+	}
+
+	hypervisors = r.Hypervisors
+	err = nil
+	return
+}
@@ -0,0 +1,149 @@
+package compute
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+
+	"github.com/gertd/go-openstack"
+	"github.com/gertd/go-openstack/identity"
+
+	"github.com/parnurzeal/gorequest"
+)
+
+type instanceActionsResp struct {
+	InstanceActions []InstanceAction `json:"instanceActions"`
+}
+
+type instanceActionResp struct {
+	InstanceAction InstanceActionDetail `json:"instanceAction"`
+}
+
+type InstanceAction struct {
+	Action       string `json:"action"`
+	InstanceUuid string `json:"instance_uuid"`
+	Message      string `json:"message"`
+	ProjectId    string `json:"project_id"`
+	RequestId    string `json:"request_id"`
+	StartTime    string `json:"start_time"`
+	UserId       string `json:"user_id"`
+}
+
+type InstanceActionDetail struct {
+	Action       string                `json:"action"`
+	InstanceUuid string                `json:"instance_uuid"`
+	Message      string                `json:"message"`
+	ProjectId    string                `json:"project_id"`
+	RequestId    string                `json:"request_id"`
+	StartTime    string                `json:"start_time"`
+	UserId       string                `json:"user_id"`
+	Events       []InstanceActionEvent `json:"events"`
+}
+
+// Traceback is only returned to callers allowed to see it by policy
+// (admin by default), it is empty otherwise.
+type InstanceActionEvent struct {
+	Event      string `json:"event"`
+	StartTime  string `json:"start_time"`
+	FinishTime string `json:"finish_time"`
+	Result     string `json:"result"`
+	Traceback  string `json:"traceback"`
+}
+
+type ByStartTime []InstanceActionDetail
+
+func (a ByStartTime) Len() int           { return len(a) }
+func (a ByStartTime) Swap(i, j int)      { a[i], a[j] = a[j], a[i] }
+func (a ByStartTime) Less(i, j int) bool { return a[i].StartTime < a[j].StartTime }
+
+func GetInstanceActions(auth identity.Auth, serverId string) (actions []InstanceAction, err error) {
+
+	req := gorequest.New()
+
+	url := fmt.Sprintf("%s/servers/%s/os-instance-actions",
+		auth.EndpointList["compute"],
+		serverId)
+
+	resp, body, errs := req.Get(url).
+		Set("Content-Type", "application/json").
+		Set("Accept", "application/json").
+		Set("X-Auth-Token", auth.Access.Token.Id).
+		End()
+
+	if errs != nil {
+		err = errs[len(errs)-1]
+		return
+	}
+
+	if err = openstack.CheckHttpResponseStatusCode(resp.StatusCode); err != nil {
+		return
+	}
+
+	var r = instanceActionsResp{}
+	if err = json.Unmarshal([]byte(body), &r); err != nil {
+		return
+	}
+
+	actions = r.InstanceActions
+	err = nil
+	return
+}
+
+func GetInstanceAction(auth identity.Auth, serverId string, requestId string) (action InstanceActionDetail, err error) {
+
+	req := gorequest.New()
+
+	url := fmt.Sprintf("%s/servers/%s/os-instance-actions/%s",
+		auth.EndpointList["compute"],
+		serverId,
+		requestId)
+
+	resp, body, errs := req.Get(url).
+		Set("Content-Type", "application/json").
+		Set("Accept", "application/json").
+		Set("X-Auth-Token", auth.Access.Token.Id).
+		End()
+
+	if errs != nil {
+		err = errs[len(errs)-1]
+		return
+	}
+
+	if err = openstack.CheckHttpResponseStatusCode(resp.StatusCode); err != nil {
+		return
+	}
+
+	var r = instanceActionResp{}
+	if err = json.Unmarshal([]byte(body), &r); err != nil {
+		return
+	}
+
+	action = r.InstanceAction
+	err = nil
+	return
+}
+
+// GetInstanceActionTimeline returns every action recorded for the server,
+// including its events, ordered from oldest to newest.
+func GetInstanceActionTimeline(auth identity.Auth, serverId string) (timeline []InstanceActionDetail, err error) {
+
+	actions, err := GetInstanceActions(auth, serverId)
+	if err != nil {
+		return
+	}
+
+	timeline = make([]InstanceActionDetail, 0, len(actions))
+	for _, v := range actions {
+		action, e := GetInstanceAction(auth, serverId, v.RequestId)
+		if e != nil {
+			err = e
+			return
+		}
+		timeline = append(timeline, action)
+	}
+
+	sort.Sort(ByStartTime(timeline))
+
+	err = nil
+	return
+}
@@ -0,0 +1,405 @@
+package compute
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/url"
+
+	"github.com/gertd/go-openstack"
+	"github.com/gertd/go-openstack/identity"
+
+	"github.com/parnurzeal/gorequest"
+)
+
+type serverMigrationsResp struct {
+	Migrations []ServerMigration `json:"migrations"`
+}
+
+type serverMigrationResp struct {
+	Migration ServerMigration `json:"migration"`
+}
+
+type migrationsResp struct {
+	Migrations []Migration      `json:"migrations"`
+	Links      []openstack.Link `json:"migrations_links"`
+}
+
+type liveMigrateReq struct {
+	LiveMigrate liveMigrate `json:"os-migrateLive"`
+}
+
+// Host is a pointer so that a nil host is sent as null, which lets the
+// scheduler pick the destination. BlockMigration is either "auto" or a bool.
+type liveMigrate struct {
+	Host           *string     `json:"host"`
+	BlockMigration interface{} `json:"block_migration"`
+	Force          bool        `json:"force,omitempty"`
+}
+
+type migrateReq struct {
+	Migrate *migrate `json:"migrate"`
+}
+
+type migrate struct {
+	Host string `json:"host,omitempty"`
+}
+
+type evacuateReq struct {
+	Evacuate EvacuateOpts `json:"evacuate"`
+}
+
+type forceCompleteReq struct {
+	ForceComplete interface{} `json:"force_complete"`
+}
+
+// LiveMigrateOpts holds the os-migrateLive parameters. An empty Host lets
+// the scheduler choose the destination, a nil BlockMigration lets nova
+// decide ("auto"), and Force bypasses the scheduler checks for Host.
+type LiveMigrateOpts struct {
+	Host           string
+	BlockMigration *bool
+	Force          bool
+}
+
+type EvacuateOpts struct {
+	Host      string `json:"host,omitempty"`
+	AdminPass string `json:"adminPass,omitempty"`
+	Force     bool   `json:"force,omitempty"`
+}
+
+type ServerMigration struct {
+	Id                   int         `json:"id"`
+	ServerUuid           string      `json:"server_uuid"`
+	Status               string      `json:"status"`
+	SourceCompute        string      `json:"source_compute"`
+	SourceNode           string      `json:"source_node"`
+	DestCompute          string      `json:"dest_compute"`
+	DestNode             string      `json:"dest_node"`
+	DestHost             string      `json:"dest_host"`
+	MemoryTotalBytes     int64       `json:"memory_total_bytes"`
+	MemoryProcessedBytes int64       `json:"memory_processed_bytes"`
+	MemoryRemainingBytes int64       `json:"memory_remaining_bytes"`
+	DiskTotalBytes       int64       `json:"disk_total_bytes"`
+	DiskProcessedBytes   int64       `json:"disk_processed_bytes"`
+	DiskRemainingBytes   int64       `json:"disk_remaining_bytes"`
+	CreatedAt            interface{} `json:"created_at"`
+	UpdatedAt            interface{} `json:"updated_at"`
+}
+
+// Uuid is only returned when GetMigrations uses microversion 2.59.
+type Migration struct {
+	Id                int         `json:"id"`
+	Uuid              string      `json:"uuid"`
+	InstanceUuid      string      `json:"instance_uuid"`
+	Status            string      `json:"status"`
+	MigrationType     string      `json:"migration_type"`
+	SourceCompute     string      `json:"source_compute"`
+	SourceNode        string      `json:"source_node"`
+	SourceRegion      string      `json:"source_region"`
+	DestCompute       string      `json:"dest_compute"`
+	DestNode          string      `json:"dest_node"`
+	DestHost          string      `json:"dest_host"`
+	DestRegion        string      `json:"dest_region"`
+	OldInstanceTypeId int         `json:"old_instance_type_id"`
+	NewInstanceTypeId int         `json:"new_instance_type_id"`
+	CreatedAt         interface{} `json:"created_at"`
+	UpdatedAt         interface{} `json:"updated_at"`
+}
+
+// MigrationFilter narrows GetMigrations, empty fields are not sent.
+// ChangesSince is an ISO 8601 timestamp, it requires compute API
+// microversion 2.59.
+type MigrationFilter struct {
+	Host          string
+	Status        string
+	InstanceUuid  string
+	SourceCompute string
+	MigrationType string
+	ChangesSince  string
+}
+
+// LiveMigrateServer requires compute API microversion 2.30.
+func LiveMigrateServer(auth identity.Auth, id string, opts LiveMigrateOpts) (err error) {
+
+	r := liveMigrateReq{liveMigrate{BlockMigration: "auto", Force: opts.Force}}
+	if len(opts.Host) > 0 {
+		r.LiveMigrate.Host = &opts.Host
+	}
+	if opts.BlockMigration != nil {
+		r.LiveMigrate.BlockMigration = *opts.BlockMigration
+	}
+
+	return serverActionReq(auth, id, r, "2.30")
+}
+
+// MigrateServer cold migrates the server, the server ends up in
+// VERIFY_RESIZE and must be confirmed with ConfirmResizeServer. Passing a
+// host requires compute API microversion 2.56.
+func MigrateServer(auth identity.Auth, id string, host string) (err error) {
+
+	if len(host) == 0 {
+		return serverActionReq(auth, id, migrateReq{}, "")
+	}
+
+	return serverActionReq(auth, id, migrateReq{&migrate{host}}, "2.56")
+}
+
+func ConfirmResizeServer(auth identity.Auth, id string) (err error) {
+
+	return serverActionReq(auth, id, map[string]interface{}{"confirmResize": nil}, "")
+}
+
+// EvacuateServer rebuilds the server of a failed host elsewhere. It requires
+// compute API microversion 2.29.
+func EvacuateServer(auth identity.Auth, id string, opts EvacuateOpts) (err error) {
+
+	return serverActionReq(auth, id, evacuateReq{opts}, "2.29")
+}
+
+func serverActionReq(auth identity.Auth, id string, action interface{}, microversion string) (err error) {
+
+	req := gorequest.New()
+
+	url := fmt.Sprintf("%s/servers/%s/action",
+		auth.EndpointList["compute"],
+		id)
+
+	b, err := json.Marshal(action)
+	if err != nil {
+		return
+	}
+
+	req.Post(url).
+		Set("Content-Type", "application/json").
+		Set("Accept", "application/json").
+		Set("X-Auth-Token", auth.Access.Token.Id)
+
+	if len(microversion) > 0 {
+		req.Set("X-OpenStack-Nova-API-Version", microversion)
+	}
+
+	resp, _, errs := req.Send(string(b)).End()
+
+	if errs != nil {
+		err = errs[len(errs)-1]
+		return
+	}
+
+	if err = openstack.CheckHttpResponseStatusCode(resp.StatusCode); err != nil {
+		return
+	}
+
+	err = nil
+	return
+}
+
+// GetServerMigrations lists the in-progress live migrations of a server. It
+// requires compute API microversion 2.23.
+func GetServerMigrations(auth identity.Auth, serverId string) (migrations []ServerMigration, err error) {
+
+	req := gorequest.New()
+
+	url := fmt.Sprintf("%s/servers/%s/migrations",
+		auth.EndpointList["compute"],
+		serverId)
+
+	resp, body, errs := req.Get(url).
+		Set("Content-Type", "application/json").
+		Set("Accept", "application/json").
+		Set("X-Auth-Token", auth.Access.Token.Id).
+		Set("X-OpenStack-Nova-API-Version", "2.23").
+		End()
+
+	if errs != nil {
+		err = errs[len(errs)-1]
+		return
+	}
+
+	if err = openstack.CheckHttpResponseStatusCode(resp.StatusCode); err != nil {
+		return
+	}
+
+	var r = serverMigrationsResp{}
+	if err = json.Unmarshal([]byte(body), &r); err != nil {
+		return
+	}
+
+	migrations = r.Migrations
+	err = nil
+	return
+}
+
+func GetServerMigration(auth identity.Auth, serverId string, migrationId int) (migration ServerMigration, err error) {
+
+	req := gorequest.New()
+
+	url := fmt.Sprintf("%s/servers/%s/migrations/%d",
+		auth.EndpointList["compute"],
+		serverId,
+		migrationId)
+
+	resp, body, errs := req.Get(url).
+		Set("Content-Type", "application/json").
+		Set("Accept", "application/json").
+		Set("X-Auth-Token", auth.Access.Token.Id).
+		Set("X-OpenStack-Nova-API-Version", "2.23").
+		End()
+
+	if errs != nil {
+		err = errs[len(errs)-1]
+		return
+	}
+
+	if err = openstack.CheckHttpResponseStatusCode(resp.StatusCode); err != nil {
+		return
+	}
+
+	var r = serverMigrationResp{}
+	if err = json.Unmarshal([]byte(body), &r); err != nil {
+		return
+	}
+
+	migration = r.Migration
+	err = nil
+	return
+}
+
+// AbortServerMigration cancels an in-progress live migration. It requires
+// compute API microversion 2.24.
+func AbortServerMigration(auth identity.Auth, serverId string, migrationId int) (err error) {
+
+	req := gorequest.New()
+
+	url := fmt.Sprintf("%s/servers/%s/migrations/%d",
+		auth.EndpointList["compute"],
+		serverId,
+		migrationId)
+
+	resp, _, errs := req.Delete(url).
+		Set("Content-Type", "application/json").
+		Set("Accept", "application/json").
+		Set("X-Auth-Token", auth.Access.Token.Id).
+		Set("X-OpenStack-Nova-API-Version", "2.24").
+		End()
+
+	if errs != nil {
+		err = errs[len(errs)-1]
+		return
+	}
+
+	if err = openstack.CheckHttpResponseStatusCode(resp.StatusCode); err != nil {
+		return
+	}
+
+	err = nil
+	return
+}
+
+// ForceCompleteServerMigration pauses the server so that a live migration
+// which does not converge can complete. It requires compute API microversion
+// 2.22.
+func ForceCompleteServerMigration(auth identity.Auth, serverId string, migrationId int) (err error) {
+
+	req := gorequest.New()
+
+	url := fmt.Sprintf("%s/servers/%s/migrations/%d/action",
+		auth.EndpointList["compute"],
+		serverId,
+		migrationId)
+
+	b, err := json.Marshal(forceCompleteReq{})
+	if err != nil {
+		return
+	}
+
+	resp, _, errs := req.Post(url).
+		Set("Content-Type", "application/json").
+		Set("Accept", "application/json").
+		Set("X-Auth-Token", auth.Access.Token.Id).
+		Set("X-OpenStack-Nova-API-Version", "2.22").
+		Send(string(b)).
+		End()
+
+	if errs != nil {
+		err = errs[len(errs)-1]
+		return
+	}
+
+	if err = openstack.CheckHttpResponseStatusCode(resp.StatusCode); err != nil {
+		return
+	}
+
+	err = nil
+	return
+}
+
+// GetMigrations lists the migrations with microversion 2.23, or 2.59 when
+// filtering on ChangesSince, following the pages of the listing.
+func GetMigrations(auth identity.Auth, filter MigrationFilter) (migrations []Migration, err error) {
+
+	microversion := "2.23"
+
+	query := url.Values{}
+	if len(filter.Host) > 0 {
+		query.Set("host", filter.Host)
+	}
+	if len(filter.Status) > 0 {
+		query.Set("status", filter.Status)
+	}
+	if len(filter.InstanceUuid) > 0 {
+		query.Set("instance_uuid", filter.InstanceUuid)
+	}
+	if len(filter.SourceCompute) > 0 {
+		query.Set("source_compute", filter.SourceCompute)
+	}
+	if len(filter.MigrationType) > 0 {
+		query.Set("migration_type", filter.MigrationType)
+	}
+	if len(filter.ChangesSince) > 0 {
+		// os-migrations ignores changes-since below 2.59
+		query.Set("changes-since", filter.ChangesSince)
+		microversion = "2.59"
+	}
+
+	reqUrl := fmt.Sprintf("%s/os-migrations?%s",
+		auth.EndpointList["compute"],
+		query.Encode())
+
+	for len(reqUrl) > 0 {
+		req := gorequest.New()
+
+		resp, body, errs := req.Get(reqUrl).
+			Set("Content-Type", "application/json").
+			Set("Accept", "application/json").
+			Set("X-Auth-Token", auth.Access.Token.Id).
+			Set("X-OpenStack-Nova-API-Version", microversion).
+			End()
+
+		if errs != nil {
+			err = errs[len(errs)-1]
+			return
+		}
+
+		if err = openstack.CheckHttpResponseStatusCode(resp.StatusCode); err != nil {
+			return
+		}
+
+		var r = migrationsResp{}
+		if err = json.Unmarshal([]byte(body), &r); err != nil {
+			return
+		}
+
+		migrations = append(migrations, r.Migrations...)
+
+		// only 2.59 pages the listing, with marker and limit in the next link
+		reqUrl = ""
+		for _, v := range r.Links {
+			if v.Rel == "next" {
+				reqUrl = v.HRef
+			}
+		}
+	}
+
+	err = nil
+	return
+}
@@ -43,7 +43,9 @@ type Server struct {
 	TaskState        string               `json:"OS-EXT-STS:task_state"`
 	VMState          string               `json:"OS-EXT-STS:vm_state"`
 	PowerState       int                  `json:"OS-EXT-STS:power_state"`
-	AvailabilityZone string               `json:"OS-EXT-AZ:availability_zone:"`
+	AvailabilityZone string               `json:"OS-EXT-AZ:availability_zone"`
+	Host             string               `json:"OS-EXT-SRV-ATTR:host"`
+	HypervisorName   string               `json:"OS-EXT-SRV-ATTR:hypervisor_hostname"`
 	UserId           string               `json:"user_id"`
 	TenantId         string               `json:"tenant_id"`
 	AccessIPv4       string               `json:"accessIPv4"`
@@ -166,12 +168,12 @@ func GetServer(auth identity.Auth, id string) (server Server, err error) {
 		Set("X-Auth-Token", auth.Access.Token.Id).
 		End()
 
-	if err = openstack.CheckHttpResponseStatusCode(resp.StatusCode); err != nil {
+	if errs != nil {
+		err = errs[len(errs)-1]
 		return
 	}
 
-	if errs != nil {
-		err = errs[len(errs)-1]
+	if err = openstack.CheckHttpResponseStatusCode(resp.StatusCode); err != nil {
 		return
 	}
 
@@ -0,0 +1,170 @@
+package compute
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/gertd/go-openstack"
+	"github.com/gertd/go-openstack/identity"
+
+	"github.com/parnurzeal/gorequest"
+)
+
+type servicesResp struct {
+	Services []Service `json:"services"`
+}
+
+type serviceResp struct {
+	Service Service `json:"service"`
+}
+
+type serviceReq struct {
+	Host           string `json:"host"`
+	Binary         string `json:"binary"`
+	DisabledReason string `json:"disabled_reason,omitempty"`
+}
+
+type serviceForceDownReq struct {
+	Host       string `json:"host"`
+	Binary     string `json:"binary"`
+	ForcedDown bool   `json:"forced_down"`
+}
+
+type Service struct {
+	Id             interface{} `json:"id"`
+	Binary         string      `json:"binary"`
+	Host           string      `json:"host"`
+	Zone           string      `json:"zone"`
+	State          string      `json:"state"`
+	Status         string      `json:"status"`
+	DisabledReason string      `json:"disabled_reason"`
+	ForcedDown     bool        `json:"forced_down"`
+	UpdatedAt      interface{} `json:"updated_at"`
+}
+
+func GetServices(auth identity.Auth) (services []Service, err error) {
+
+	req := gorequest.New()
+
+	url := fmt.Sprintf("%s/os-services",
+		auth.EndpointList["compute"])
+
+	resp, body, errs := req.Get(url).
+		Set("Content-Type", "application/json").
+		Set("Accept", "application/json").
+		Set("X-Auth-Token", auth.Access.Token.Id).
+		End()
+
+	if errs != nil {
+		err = errs[len(errs)-1]
+		return
+	}
+
+	if err = openstack.CheckHttpResponseStatusCode(resp.StatusCode); err != nil {
+		return
+	}
+
+	var r = servicesResp{}
+	if err = json.Unmarshal([]byte(body), &r); err != nil {
+		return
+	}
+
+	services = r.Services
+	err = nil
+	return
+}
+
+func EnableService(auth identity.Auth, host string, binary string) (service Service, err error) {
+
+	return updateService(auth, "enable", serviceReq{Host: host, Binary: binary})
+}
+
+func DisableService(auth identity.Auth, host string, binary string, reason string) (service Service, err error) {
+
+	if len(reason) == 0 {
+		return updateService(auth, "disable", serviceReq{Host: host, Binary: binary})
+	}
+
+	return updateService(auth, "disable-log-reason", serviceReq{Host: host, Binary: binary, DisabledReason: reason})
+}
+
+func updateService(auth identity.Auth, action string, serviceReq serviceReq) (service Service, err error) {
+
+	req := gorequest.New()
+
+	url := fmt.Sprintf("%s/os-services/%s",
+		auth.EndpointList["compute"],
+		action)
+
+	b, err := json.Marshal(serviceReq)
+	if err != nil {
+		return
+	}
+
+	resp, body, errs := req.Put(url).
+		Set("Content-Type", "application/json").
+		Set("Accept", "application/json").
+		Set("X-Auth-Token", auth.Access.Token.Id).
+		Send(string(b)).
+		End()
+
+	if errs != nil {
+		err = errs[len(errs)-1]
+		return
+	}
+
+	if err = openstack.CheckHttpResponseStatusCode(resp.StatusCode); err != nil {
+		return
+	}
+
+	var r = serviceResp{}
+	if err = json.Unmarshal([]byte(body), &r); err != nil {
+		return
+	}
+
+	service = r.Service
+	err = nil
+	return
+}
+
+// ForceDownService marks the service as down without waiting for it to miss
+// its heartbeats, which allows servers to be evacuated from a failed host
+// immediately. It requires compute API microversion 2.11.
+func ForceDownService(auth identity.Auth, host string, binary string, forcedDown bool) (service Service, err error) {
+
+	req := gorequest.New()
+
+	url := fmt.Sprintf("%s/os-services/force-down",
+		auth.EndpointList["compute"])
+
+	b, err := json.Marshal(serviceForceDownReq{host, binary, forcedDown})
+	if err != nil {
+		return
+	}
+
+	resp, body, errs := req.Put(url).
+		Set("Content-Type", "application/json").
+		Set("Accept", "application/json").
+		Set("X-Auth-Token", auth.Access.Token.Id).
+		Set("X-OpenStack-Nova-API-Version", "2.11").
+		Send(string(b)).
+		End()
+
+	if errs != nil {
+		err = errs[len(errs)-1]
+		return
+	}
+
+	if err = openstack.CheckHttpResponseStatusCode(resp.StatusCode); err != nil {
+		return
+	}
+
+	var r = serviceResp{}
+	if err = json.Unmarshal([]byte(body), &r); err != nil {
+		return
+	}
+
+	service = r.Service
+	err = nil
+	return
+}
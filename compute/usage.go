@@ -0,0 +1,242 @@
+package compute
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"sort"
+	"time"
+
+	"github.com/gertd/go-openstack"
+	"github.com/gertd/go-openstack/identity"
+
+	"github.com/parnurzeal/gorequest"
+)
+
+const usageTimeFormat = "2006-01-02T15:04:05.000000"
+
+// usagePageSize is the number of servers requested per page, pagination
+// requires compute API microversion 2.40.
+const usagePageSize = 1000
+
+type tenantUsagesResp struct {
+	TenantUsages      []TenantUsage    `json:"tenant_usages"`
+	TenantUsagesLinks []openstack.Link `json:"tenant_usages_links"`
+}
+
+type tenantUsageResp struct {
+	TenantUsage      TenantUsage      `json:"tenant_usage"`
+	TenantUsageLinks []openstack.Link `json:"tenant_usage_links"`
+}
+
+type TenantUsage struct {
+	TenantId           string        `json:"tenant_id"`
+	Start              string        `json:"start"`
+	Stop               string        `json:"stop"`
+	TotalHours         float64       `json:"total_hours"`
+	TotalVCPUsUsage    float64       `json:"total_vcpus_usage"`
+	TotalMemoryMBUsage float64       `json:"total_memory_mb_usage"`
+	TotalLocalGBUsage  float64       `json:"total_local_gb_usage"`
+	ServerUsages       []ServerUsage `json:"server_usages"`
+}
+
+// Flavor holds the flavor name, EndedAt is empty for servers which still
+// exist.
+type ServerUsage struct {
+	InstanceId string  `json:"instance_id"`
+	Name       string  `json:"name"`
+	TenantId   string  `json:"tenant_id"`
+	State      string  `json:"state"`
+	Flavor     string  `json:"flavor"`
+	Hours      float64 `json:"hours"`
+	VCPUs      int     `json:"vcpus"`
+	MemoryMB   int     `json:"memory_mb"`
+	LocalGB    int     `json:"local_gb"`
+	Uptime     int     `json:"uptime"`
+	StartedAt  string  `json:"started_at"`
+	EndedAt    string  `json:"ended_at"`
+}
+
+type UsageTotals struct {
+	Tenants            int
+	Servers            int
+	TotalHours         float64
+	TotalVCPUsUsage    float64
+	TotalMemoryMBUsage float64
+	TotalLocalGBUsage  float64
+}
+
+type ByTenantId []TenantUsage
+
+func (a ByTenantId) Len() int           { return len(a) }
+func (a ByTenantId) Swap(i, j int)      { a[i], a[j] = a[j], a[i] }
+func (a ByTenantId) Less(i, j int) bool { return a[i].TenantId < a[j].TenantId }
+
+// GetTenantUsages returns the usage of every tenant between start and end,
+// following the pagination links and merging the pages of a tenant.
+func GetTenantUsages(auth identity.Auth, start time.Time, end time.Time) (usages []TenantUsage, err error) {
+
+	query := usageQuery(start, end)
+	query.Set("detailed", "1")
+
+	reqUrl := fmt.Sprintf("%s/os-simple-tenant-usage?%s",
+		auth.EndpointList["compute"],
+		query.Encode())
+
+	merged := map[string]*TenantUsage{}
+
+	for len(reqUrl) > 0 {
+		var r = tenantUsagesResp{}
+		if err = getUsagePage(auth, reqUrl, &r); err != nil {
+			return
+		}
+
+		for _, v := range r.TenantUsages {
+			if u, ok := merged[v.TenantId]; ok {
+				u.merge(v)
+			} else {
+				u := v
+				merged[v.TenantId] = &u
+			}
+		}
+
+		reqUrl = nextUsagePage(r.TenantUsagesLinks)
+	}
+
+	usages = make([]TenantUsage, 0, len(merged))
+	for _, v := range merged {
+		usages = append(usages, *v)
+	}
+	sort.Sort(ByTenantId(usages))
+
+	err = nil
+	return
+}
+
+// GetTenantUsage returns the usage of a single tenant between start and end,
+// following the pagination links.
+func GetTenantUsage(auth identity.Auth, tenantId string, start time.Time, end time.Time) (usage TenantUsage, err error) {
+
+	reqUrl := fmt.Sprintf("%s/os-simple-tenant-usage/%s?%s",
+		auth.EndpointList["compute"],
+		tenantId,
+		usageQuery(start, end).Encode())
+
+	first := true
+
+	for len(reqUrl) > 0 {
+		var r = tenantUsageResp{}
+		if err = getUsagePage(auth, reqUrl, &r); err != nil {
+			return
+		}
+
+		if first {
+			usage = r.TenantUsage
+			first = false
+		} else {
+			usage.merge(r.TenantUsage)
+		}
+
+		reqUrl = nextUsagePage(r.TenantUsageLinks)
+	}
+
+	err = nil
+	return
+}
+
+func usageQuery(start time.Time, end time.Time) (query url.Values) {
+
+	query = url.Values{}
+	query.Set("start", start.UTC().Format(usageTimeFormat))
+	query.Set("end", end.UTC().Format(usageTimeFormat))
+	query.Set("limit", fmt.Sprintf("%d", usagePageSize))
+
+	return query
+}
+
+func nextUsagePage(links []openstack.Link) string {
+
+	for _, v := range links {
+		if v.Rel == "next" {
+			return v.HRef
+		}
+	}
+
+	return ""
+}
+
+func getUsagePage(auth identity.Auth, url string, v interface{}) (err error) {
+
+	req := gorequest.New()
+
+	resp, body, errs := req.Get(url).
+		Set("Content-Type", "application/json").
+		Set("Accept", "application/json").
+		Set("X-Auth-Token", auth.Access.Token.Id).
+		Set("X-OpenStack-Nova-API-Version", "2.40").
+		End()
+
+	if errs != nil {
+		err = errs[len(errs)-1]
+		return
+	}
+
+	if err = openstack.CheckHttpResponseStatusCode(resp.StatusCode); err != nil {
+		return
+	}
+
+	if err = json.Unmarshal([]byte(body), v); err != nil {
+		return
+	}
+
+	err = nil
+	return
+}
+
+func (u *TenantUsage) merge(page TenantUsage) {
+
+	u.TotalHours += page.TotalHours
+	u.TotalVCPUsUsage += page.TotalVCPUsUsage
+	u.TotalMemoryMBUsage += page.TotalMemoryMBUsage
+	u.TotalLocalGBUsage += page.TotalLocalGBUsage
+	u.ServerUsages = append(u.ServerUsages, page.ServerUsages...)
+}
+
+// Totals aggregates the usage of several tenants.
+func Totals(usages []TenantUsage) (totals UsageTotals) {
+
+	for _, v := range usages {
+		totals.Tenants++
+		totals.Servers += len(v.ServerUsages)
+		totals.TotalHours += v.TotalHours
+		totals.TotalVCPUsUsage += v.TotalVCPUsUsage
+		totals.TotalMemoryMBUsage += v.TotalMemoryMBUsage
+		totals.TotalLocalGBUsage += v.TotalLocalGBUsage
+	}
+
+	return totals
+}
+
+// Cost prices the usage of the tenant from an hourly price per flavor, keyed
+// by Flavor.Name. Flavors missing from prices are not charged and are
+// returned in unpriced.
+func (u TenantUsage) Cost(prices map[string]float64) (cost float64, unpriced []string) {
+
+	missing := map[string]bool{}
+
+	for _, v := range u.ServerUsages {
+		price, ok := prices[v.Flavor]
+		if !ok {
+			if !missing[v.Flavor] {
+				missing[v.Flavor] = true
+				unpriced = append(unpriced, v.Flavor)
+			}
+			continue
+		}
+		cost += price * v.Hours
+	}
+
+	sort.Strings(unpriced)
+
+	return cost, unpriced
+}
@@ -0,0 +1,24 @@
+package openstack
+
+import (
+	"regexp"
+	"strings"
+)
+
+var versionSuffix = regexp.MustCompile(`^v[0-9]+(\.[0-9]+)?$`)
+
+// UnversionedEndpoint returns the catalog endpoint without its version
+// suffix, services such as image and load-balancer may be registered with
+// or without one.
+func UnversionedEndpoint(endpoint string) string {
+
+	endpoint = strings.TrimRight(endpoint, "/")
+
+	if i := strings.LastIndex(endpoint, "/"); i >= 0 {
+		if versionSuffix.MatchString(endpoint[i+1:]) {
+			endpoint = endpoint[:i]
+		}
+	}
+
+	return endpoint
+}
@@ -0,0 +1,255 @@
+package image
+
+import (
+	"crypto/md5"
+	"crypto/sha1"
+	"crypto/sha256"
+	"crypto/sha512"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"hash"
+	"io"
+	"net/http"
+
+	"github.com/gertd/go-openstack"
+	"github.com/gertd/go-openstack/identity"
+
+	"github.com/parnurzeal/gorequest"
+)
+
+// ProgressFunc is called as image data is transferred with the number of
+// bytes transferred so far and the total, total is -1 when unknown.
+type ProgressFunc func(transferred int64, total int64)
+
+// NewImage is the image record created by CreateImage. Properties are sent
+// as additional top level attributes.
+type NewImage struct {
+	Id              string            `json:"id,omitempty"`
+	Name            string            `json:"name,omitempty"`
+	DiskFormat      string            `json:"disk_format,omitempty"`
+	ContainerFormat string            `json:"container_format,omitempty"`
+	Visibility      string            `json:"visibility,omitempty"`
+	Protected       bool              `json:"protected,omitempty"`
+	Hidden          bool              `json:"os_hidden,omitempty"`
+	MinDisk         int               `json:"min_disk,omitempty"`
+	MinRam          int               `json:"min_ram,omitempty"`
+	Tags            []string          `json:"tags,omitempty"`
+	Properties      map[string]string `json:"-"`
+}
+
+func (image NewImage) MarshalJSON() (b []byte, err error) {
+
+	type newImage NewImage
+
+	b, err = json.Marshal(newImage(image))
+	if err != nil || len(image.Properties) == 0 {
+		return
+	}
+
+	var attrs map[string]interface{}
+	if err = json.Unmarshal(b, &attrs); err != nil {
+		return
+	}
+
+	for k, v := range image.Properties {
+		if imageSchemaKeys[k] {
+			err = errors.New(fmt.Sprintf("property %s is reserved", k))
+			return
+		}
+		attrs[k] = v
+	}
+
+	return json.Marshal(attrs)
+}
+
+type progressReader struct {
+	reader      io.Reader
+	transferred int64
+	total       int64
+	progress    ProgressFunc
+}
+
+func (r *progressReader) Read(p []byte) (n int, err error) {
+
+	n, err = r.reader.Read(p)
+	if n > 0 {
+		r.transferred += int64(n)
+		if r.progress != nil {
+			r.progress(r.transferred, r.total)
+		}
+	}
+
+	return
+}
+
+// CreateImage creates the image record, the image stays queued until data
+// is uploaded.
+func CreateImage(auth identity.Auth, newImage NewImage) (image ImageDetail, err error) {
+
+	req := gorequest.New()
+
+	url := fmt.Sprintf("%s/v2/images",
+		imageEndpoint(auth))
+
+	b, err := json.Marshal(newImage)
+	if err != nil {
+		return
+	}
+
+	resp, body, errs := req.Post(url).
+		Set("Content-Type", "application/json").
+		Set("Accept", "application/json").
+		Set("X-Auth-Token", auth.Access.Token.Id).
+		Send(string(b)).
+		End()
+
+	if errs != nil {
+		err = errs[len(errs)-1]
+		return
+	}
+
+	if err = openstack.CheckHttpResponseStatusCode(resp.StatusCode); err != nil {
+		return
+	}
+
+	if err = json.Unmarshal([]byte(body), &image); err != nil {
+		return
+	}
+
+	err = nil
+	return
+}
+
+// UploadImageData streams the image data from r without buffering it. Pass
+// the size when known so that it is sent as the Content-Length, -1
+// otherwise. progress may be nil.
+func UploadImageData(auth identity.Auth, id string, r io.Reader, size int64, progress ProgressFunc) (err error) {
+
+	url := fmt.Sprintf("%s/v2/images/%s/file",
+		imageEndpoint(auth),
+		id)
+
+	return putImageData(auth, url, r, size, progress)
+}
+
+func putImageData(auth identity.Auth, url string, r io.Reader, size int64, progress ProgressFunc) (err error) {
+
+	body := &progressReader{reader: r, total: size, progress: progress}
+
+	req, err := http.NewRequest("PUT", url, body)
+	if err != nil {
+		return
+	}
+
+	req.Header.Set("Content-Type", "application/octet-stream")
+	req.Header.Set("X-Auth-Token", auth.Access.Token.Id)
+	if size >= 0 {
+		req.ContentLength = size
+	}
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return
+	}
+	defer resp.Body.Close()
+
+	if err = openstack.CheckHttpResponseStatusCode(resp.StatusCode); err != nil {
+		return
+	}
+
+	err = nil
+	return
+}
+
+// DownloadImageData streams the image data to w and verifies the checksum
+// and multihash of the image as it goes. When verification fails an error
+// is returned after the data has been written, the caller must discard it.
+// progress may be nil.
+func DownloadImageData(auth identity.Auth, id string, w io.Writer, progress ProgressFunc) (err error) {
+
+	image, err := GetImageById(auth, id)
+	if err != nil {
+		return
+	}
+
+	url := fmt.Sprintf("%s/v2/images/%s/file",
+		imageEndpoint(auth),
+		id)
+
+	req, err := http.NewRequest("GET", url, nil)
+	if err != nil {
+		return
+	}
+
+	req.Header.Set("Accept", "application/octet-stream")
+	req.Header.Set("X-Auth-Token", auth.Access.Token.Id)
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return
+	}
+	defer resp.Body.Close()
+
+	if err = openstack.CheckHttpResponseStatusCode(resp.StatusCode); err != nil {
+		return
+	}
+
+	writers := []io.Writer{w}
+
+	checksum := md5.New()
+	if len(image.CheckSum) > 0 {
+		writers = append(writers, checksum)
+	}
+
+	var multihash hash.Hash
+	if len(image.HashValue) > 0 {
+		if multihash, err = newHash(image.HashAlgo); err != nil {
+			return
+		}
+		writers = append(writers, multihash)
+	}
+
+	body := &progressReader{reader: resp.Body, total: resp.ContentLength, progress: progress}
+
+	if _, err = io.Copy(io.MultiWriter(writers...), body); err != nil {
+		return
+	}
+
+	if len(image.CheckSum) > 0 {
+		if sum := hex.EncodeToString(checksum.Sum(nil)); sum != image.CheckSum {
+			err = errors.New(fmt.Sprintf("image %s checksum mismatch: expected %s, got %s", id, image.CheckSum, sum))
+			return
+		}
+	}
+
+	if multihash != nil {
+		if sum := hex.EncodeToString(multihash.Sum(nil)); sum != image.HashValue {
+			err = errors.New(fmt.Sprintf("image %s %s mismatch: expected %s, got %s", id, image.HashAlgo, image.HashValue, sum))
+			return
+		}
+	}
+
+	err = nil
+	return
+}
+
+func newHash(algo string) (h hash.Hash, err error) {
+
+	switch algo {
+	case "md5":
+		return md5.New(), nil
+	case "sha1":
+		return sha1.New(), nil
+	case "sha256":
+		return sha256.New(), nil
+	case "sha384":
+		return sha512.New384(), nil
+	case "sha512":
+		return sha512.New(), nil
+	}
+
+	err = errors.New(fmt.Sprintf("unsupported hash algorithm %s", algo))
+	return
+}
@@ -4,6 +4,8 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"net/url"
+	"strconv"
 
 	"github.com/gertd/go-openstack"
 	"github.com/gertd/go-openstack/identity"
@@ -15,8 +17,9 @@ type imagesResp struct {
 	Images []Image `json:"images"`
 }
 
-type imagesDetailResp struct {
+type imagesV2Resp struct {
 	Images []ImageDetail `json:"images"`
+	Next   string        `json:"next"`
 }
 
 type Image struct {
@@ -28,24 +31,120 @@ type Image struct {
 	//Size            int    `json:"size,omitempty"`
 }
 
+// ImageDetail models the Glance v2 image schema. Properties holds every
+// additional top level attribute, such as os_distro or hw_disk_bus.
 type ImageDetail struct {
 	Id              string            `json:"id"`
 	Name            string            `json:"name"`
 	Status          string            `json:"status"`
+	Visibility      string            `json:"visibility"`
+	Protected       bool              `json:"protected"`
+	Hidden          bool              `json:"os_hidden"`
 	Owner           string            `json:"owner"`
-	Created         interface{}       `json:"created_at,omitempty"`
-	Updated         interface{}       `json:"updated_at,omitempty"`
-	Deleted         interface{}       `json:"deleted_at,omitempty"`
-	IsDeleted       bool              `json:"deleted"`
+	Created         string            `json:"created_at"`
+	Updated         string            `json:"updated_at"`
 	ContainerFormat string            `json:"container_format"`
 	DiskFormat      string            `json:"disk_format"`
 	CheckSum        string            `json:"checksum"`
-	Size            int               `json:"size"`
-	Protected       bool              `json:"protected"`
-	IsPublic        bool              `json:"is_public"`
-	MinDisk         int               `json:"minDisk"`
-	MinRam          int               `json:"minRam"`
-	Properties      map[string]string `json:"properties"`
+	HashAlgo        string            `json:"os_hash_algo"`
+	HashValue       string            `json:"os_hash_value"`
+	Size            int64             `json:"size"`
+	VirtualSize     int64             `json:"virtual_size"`
+	MinDisk         int               `json:"min_disk"`
+	MinRam          int               `json:"min_ram"`
+	Tags            []string          `json:"tags"`
+	File            string            `json:"file"`
+	Self            string            `json:"self"`
+	Schema          string            `json:"schema"`
+	Stores          string            `json:"stores"`
+	ImportingStores string            `json:"os_glance_importing_to_stores"`
+	FailedStores    string            `json:"os_glance_failed_import"`
+	Properties      map[string]string `json:"-"`
+}
+
+// ImageListOpts filters GetImagesDetail, empty fields are not sent. Sort
+// uses the Glance v2 syntax, e.g. "name:asc,created_at:desc". Limit is the
+// page size, all pages are always returned.
+type ImageListOpts struct {
+	Name         string
+	Visibility   string
+	Status       string
+	Tags         []string
+	Owner        string
+	MemberStatus string
+	Sort         string
+	Limit        int
+}
+
+const (
+	VisibilityPublic    = "public"
+	VisibilityPrivate   = "private"
+	VisibilityShared    = "shared"
+	VisibilityCommunity = "community"
+)
+
+// imageSchemaKeys are the attributes mapped to ImageDetail fields, any other
+// top level attribute is a custom property.
+var imageSchemaKeys = map[string]bool{
+	"id":                            true,
+	"name":                          true,
+	"status":                        true,
+	"visibility":                    true,
+	"protected":                     true,
+	"os_hidden":                     true,
+	"owner":                         true,
+	"created_at":                    true,
+	"updated_at":                    true,
+	"container_format":              true,
+	"disk_format":                   true,
+	"checksum":                      true,
+	"os_hash_algo":                  true,
+	"os_hash_value":                 true,
+	"size":                          true,
+	"virtual_size":                  true,
+	"min_disk":                      true,
+	"min_ram":                       true,
+	"tags":                          true,
+	"file":                          true,
+	"self":                          true,
+	"schema":                        true,
+	"stores":                        true,
+	"os_glance_importing_to_stores": true,
+	"os_glance_failed_import":       true,
+	"locations":                     true,
+	"direct_url":                    true,
+}
+
+func (image *ImageDetail) UnmarshalJSON(b []byte) (err error) {
+
+	type imageDetail ImageDetail
+
+	var d imageDetail
+	if err = json.Unmarshal(b, &d); err != nil {
+		return
+	}
+
+	var attrs map[string]json.RawMessage
+	if err = json.Unmarshal(b, &attrs); err != nil {
+		return
+	}
+
+	d.Properties = make(map[string]string)
+	for k, v := range attrs {
+		if imageSchemaKeys[k] {
+			continue
+		}
+		var s string
+		if json.Unmarshal(v, &s) == nil {
+			d.Properties[k] = s
+		} else {
+			d.Properties[k] = string(v)
+		}
+	}
+
+	*image = ImageDetail(d)
+	err = nil
+	return
 }
 
 func GetImages(url string, token identity.Token) (images []Image, err error) {
@@ -60,13 +159,11 @@ func GetImages(url string, token identity.Token) (images []Image, err error) {
 
 	if errs != nil {
 		err = errs[len(errs)-1]
-		fmt.Println("err1", err)
 		return
 	}
 
 	var r = imagesResp{}
 	if err = json.Unmarshal([]byte(body), &r); err != nil {
-		fmt.Println("err2", err)
 		return
 	}
 
@@ -75,7 +172,70 @@ func GetImages(url string, token identity.Token) (images []Image, err error) {
 	return
 }
 
-func GetImagesDetail(url string, token identity.Token) (images []ImageDetail, err error) {
+// GetImagesDetail lists the images using the Glance v2 API, following the
+// next links until every page has been read.
+func GetImagesDetail(auth identity.Auth, opts ImageListOpts) (images []ImageDetail, err error) {
+
+	query := url.Values{}
+	if len(opts.Name) > 0 {
+		query.Set("name", opts.Name)
+	}
+	if len(opts.Visibility) > 0 {
+		query.Set("visibility", opts.Visibility)
+	}
+	if len(opts.Status) > 0 {
+		query.Set("status", opts.Status)
+	}
+	for _, v := range opts.Tags {
+		query.Add("tag", v)
+	}
+	if len(opts.Owner) > 0 {
+		query.Set("owner", opts.Owner)
+	}
+	if len(opts.MemberStatus) > 0 {
+		query.Set("member_status", opts.MemberStatus)
+	}
+	if len(opts.Sort) > 0 {
+		query.Set("sort", opts.Sort)
+	}
+	if opts.Limit > 0 {
+		query.Set("limit", strconv.Itoa(opts.Limit))
+	}
+
+	reqUrl := fmt.Sprintf("%s/v2/images?%s",
+		imageEndpoint(auth),
+		query.Encode())
+
+	for len(reqUrl) > 0 {
+		req := gorequest.New()
+
+		resp, body, errs := req.Get(reqUrl).
+			Set("Content-Type", "application/json").
+			Set("Accept", "application/json").
+			Set("X-Auth-Token", auth.Access.Token.Id).
+			End()
+
+		if errs != nil {
+			err = errs[len(errs)-1]
+			return
+		}
+
+		if err = openstack.CheckHttpResponseStatusCode(resp.StatusCode); err != nil {
+			return
+		}
+
+		var r = imagesV2Resp{}
+		if err = json.Unmarshal([]byte(body), &r); err != nil {
+			return
+		}
+
+		images = append(images, r.Images...)
+
+		reqUrl = ""
+		if len(r.Next) > 0 {
+			reqUrl = imageEndpoint(auth) + r.Next
+		}
+	}
 
 	err = nil
 	return
@@ -85,11 +245,11 @@ func GetImage(auth identity.Auth, name string) (image Image, err error) {
 
 	req := gorequest.New()
 
-	url := fmt.Sprintf("%s/images?limit=20&name=%s",
+	reqUrl := fmt.Sprintf("%s/images?limit=20&name=%s",
 		auth.EndpointList["image"],
-		name)
+		url.QueryEscape(name))
 
-	_, body, errs := req.Get(url).
+	_, body, errs := req.Get(reqUrl).
 		Set("Content-Type", "application/json").
 		Set("Accept", "application/json").
 		Set("X-Auth-Token", auth.Access.Token.Id).
@@ -122,11 +282,33 @@ func GetImage(auth identity.Auth, name string) (image Image, err error) {
 
 func GetImageDetail(auth identity.Auth, name string) (image ImageDetail, err error) {
 
+	images, err := GetImagesDetail(auth, ImageListOpts{Name: name})
+	if err != nil {
+		return
+	}
+
+	if len(images) == 0 {
+		err = errors.New(fmt.Sprintf("image %s not found", name))
+		return
+	}
+	if len(images) > 1 {
+		err = errors.New(fmt.Sprintf("image %s multiple entries found", name))
+		return
+	}
+
+	image = images[0]
+
+	err = nil
+	return
+}
+
+func GetImageById(auth identity.Auth, id string) (image ImageDetail, err error) {
+
 	req := gorequest.New()
 
-	url := fmt.Sprintf("%s/images/detail?limit=20&name=%s",
-		auth.EndpointList["image"],
-		name)
+	url := fmt.Sprintf("%s/v2/images/%s",
+		imageEndpoint(auth),
+		id)
 
 	resp, body, errs := req.Get(url).
 		Set("Content-Type", "application/json").
@@ -134,31 +316,24 @@ func GetImageDetail(auth identity.Auth, name string) (image ImageDetail, err err
 		Set("X-Auth-Token", auth.Access.Token.Id).
 		End()
 
-	if err = openstack.CheckHttpResponseStatusCode(resp.StatusCode); err != nil {
-		return
-	}
-
 	if errs != nil {
 		err = errs[len(errs)-1]
 		return
 	}
 
-	var r = imagesDetailResp{}
-	if err = json.Unmarshal([]byte(body), &r); err != nil {
+	if err = openstack.CheckHttpResponseStatusCode(resp.StatusCode); err != nil {
 		return
 	}
 
-	if len(r.Images) == 0 {
-		err = errors.New(fmt.Sprintf("image %s not found", name))
-		return
-	}
-	if len(r.Images) > 1 {
-		err = errors.New(fmt.Sprintf("image %s multiple entries found", name))
+	if err = json.Unmarshal([]byte(body), &image); err != nil {
 		return
 	}
 
-	image = r.Images[0]
-
 	err = nil
 	return
 }
+
+func imageEndpoint(auth identity.Auth) string {
+
+	return openstack.UnversionedEndpoint(auth.EndpointList["image"])
+}
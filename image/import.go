@@ -0,0 +1,271 @@
+package image
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/gertd/go-openstack"
+	"github.com/gertd/go-openstack/identity"
+
+	"github.com/parnurzeal/gorequest"
+)
+
+const (
+	ImportWebDownload  = "web-download"
+	ImportGlanceDirect = "glance-direct"
+	ImportCopyImage    = "copy-image"
+)
+
+const (
+	importPollInterval   = 5 * time.Second
+	defaultImportTimeout = time.Hour
+)
+
+type importInfoResp struct {
+	ImportMethods struct {
+		Value []string `json:"value"`
+	} `json:"import-methods"`
+}
+
+type storesResp struct {
+	Stores []Store `json:"stores"`
+}
+
+type Store struct {
+	Id          string `json:"id"`
+	Description string `json:"description"`
+	Default     bool   `json:"default"`
+	ReadOnly    bool   `json:"read-only"`
+}
+
+type importReq struct {
+	Method               importMethod `json:"method"`
+	Stores               []string     `json:"stores,omitempty"`
+	AllStores            bool         `json:"all_stores,omitempty"`
+	AllStoresMustSucceed *bool        `json:"all_stores_must_succeed,omitempty"`
+}
+
+type importMethod struct {
+	Name string `json:"name"`
+	URI  string `json:"uri,omitempty"`
+}
+
+// ImportOpts describes an import. URI is only used by web-download. Stores
+// lists the target stores, or AllStores targets every store. Glance fails
+// the whole import on the first store failure unless AllStoresMustSucceed
+// is set to false.
+type ImportOpts struct {
+	Method               string
+	URI                  string
+	Stores               []string
+	AllStores            bool
+	AllStoresMustSucceed *bool
+}
+
+// ImportResult reports the stores holding the image data and the stores
+// the import failed for.
+type ImportResult struct {
+	Image        ImageDetail
+	Stores       []string
+	FailedStores []string
+}
+
+func GetImportMethods(auth identity.Auth) (methods []string, err error) {
+
+	req := gorequest.New()
+
+	url := fmt.Sprintf("%s/v2/info/import",
+		imageEndpoint(auth))
+
+	resp, body, errs := req.Get(url).
+		Set("Content-Type", "application/json").
+		Set("Accept", "application/json").
+		Set("X-Auth-Token", auth.Access.Token.Id).
+		End()
+
+	if errs != nil {
+		err = errs[len(errs)-1]
+		return
+	}
+
+	if err = openstack.CheckHttpResponseStatusCode(resp.StatusCode); err != nil {
+		return
+	}
+
+	var r = importInfoResp{}
+	if err = json.Unmarshal([]byte(body), &r); err != nil {
+		return
+	}
+
+	methods = r.ImportMethods.Value
+	err = nil
+	return
+}
+
+// GetStores lists the stores of a multi-store cloud.
+func GetStores(auth identity.Auth) (stores []Store, err error) {
+
+	req := gorequest.New()
+
+	url := fmt.Sprintf("%s/v2/info/stores",
+		imageEndpoint(auth))
+
+	resp, body, errs := req.Get(url).
+		Set("Content-Type", "application/json").
+		Set("Accept", "application/json").
+		Set("X-Auth-Token", auth.Access.Token.Id).
+		End()
+
+	if errs != nil {
+		err = errs[len(errs)-1]
+		return
+	}
+
+	if err = openstack.CheckHttpResponseStatusCode(resp.StatusCode); err != nil {
+		return
+	}
+
+	var r = storesResp{}
+	if err = json.Unmarshal([]byte(body), &r); err != nil {
+		return
+	}
+
+	stores = r.Stores
+	err = nil
+	return
+}
+
+// StageImageData streams the image data to the staging area, the data is
+// moved to the stores by a glance-direct import.
+func StageImageData(auth identity.Auth, id string, r io.Reader, size int64, progress ProgressFunc) (err error) {
+
+	url := fmt.Sprintf("%s/v2/images/%s/stage",
+		imageEndpoint(auth),
+		id)
+
+	return putImageData(auth, url, r, size, progress)
+}
+
+// ImportImage starts the import, it returns as soon as Glance accepted it.
+// Use WaitForImport to follow it.
+func ImportImage(auth identity.Auth, id string, opts ImportOpts) (err error) {
+
+	req := gorequest.New()
+
+	url := fmt.Sprintf("%s/v2/images/%s/import",
+		imageEndpoint(auth),
+		id)
+
+	r := importReq{
+		Method:               importMethod{opts.Method, opts.URI},
+		Stores:               opts.Stores,
+		AllStores:            opts.AllStores,
+		AllStoresMustSucceed: opts.AllStoresMustSucceed,
+	}
+
+	b, err := json.Marshal(r)
+	if err != nil {
+		return
+	}
+
+	resp, _, errs := req.Post(url).
+		Set("Content-Type", "application/json").
+		Set("Accept", "application/json").
+		Set("X-Auth-Token", auth.Access.Token.Id).
+		Send(string(b)).
+		End()
+
+	if errs != nil {
+		err = errs[len(errs)-1]
+		return
+	}
+
+	if err = openstack.CheckHttpResponseStatusCode(resp.StatusCode); err != nil {
+		return
+	}
+
+	err = nil
+	return
+}
+
+// WaitForImport polls the image until no store import is pending. An error
+// is returned when the import failed for a store and no store is left or
+// the image went back to queued, result then tells which stores failed, or
+// when the image left the importing status without becoming active, which
+// is how Glance reports a failed web-download or glance-direct import. A
+// timeout of zero waits up to an hour.
+func WaitForImport(auth identity.Auth, id string, timeout time.Duration) (result ImportResult, err error) {
+
+	if timeout <= 0 {
+		timeout = defaultImportTimeout
+	}
+
+	start := time.Now()
+	started := false
+
+	for {
+		time.Sleep(importPollInterval)
+
+		var image ImageDetail
+		if image, err = GetImageById(auth, id); err != nil {
+			return
+		}
+
+		result = ImportResult{
+			Image:        image,
+			Stores:       splitStores(image.Stores),
+			FailedStores: splitStores(image.FailedStores),
+		}
+
+		if image.Status == "killed" || image.Status == "deleted" {
+			err = errors.New(fmt.Sprintf("image %s import failed, image is %s", id, image.Status))
+			return
+		}
+
+		pending := len(splitStores(image.ImportingStores)) > 0
+		inProgress := image.Status == "importing"
+		if pending || inProgress {
+			started = true
+		}
+
+		// Glance reverts the image to queued on failure and may leave the
+		// remaining stores listed as importing, an import which failed
+		// before the first poll would never look started otherwise
+		if len(result.FailedStores) > 0 && (!pending || image.Status == "queued") {
+			err = errors.New(fmt.Sprintf("image %s import failed for stores %s", id, strings.Join(result.FailedStores, ", ")))
+			return
+		}
+
+		if !pending && !inProgress {
+			if image.Status == "active" {
+				err = nil
+				return
+			}
+			// queued or uploading again once the import has run
+			if started {
+				err = errors.New(fmt.Sprintf("image %s import failed, image is %s", id, image.Status))
+				return
+			}
+		}
+
+		if time.Since(start) > timeout {
+			err = errors.New(fmt.Sprintf("timeout waiting for image %s import (status %s)", id, image.Status))
+			return
+		}
+	}
+}
+
+func splitStores(stores string) (list []string) {
+
+	for _, v := range strings.Split(stores, ",") {
+		if v = strings.TrimSpace(v); len(v) > 0 {
+			list = append(list, v)
+		}
+	}
+
+	return list
+}
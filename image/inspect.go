@@ -0,0 +1,236 @@
+package image
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/gertd/go-openstack/identity"
+)
+
+// inspectHeaderSize covers the ISO 9660 primary volume descriptor, which is
+// the furthest signature from the start of the image.
+const inspectHeaderSize = 64 * 1024
+
+const vhdFooterSize = 512
+
+const gib = 1024 * 1024 * 1024
+
+// DiskInfo is what could be learnt from the image data. VirtualSize is in
+// bytes and zero when unknown. BackingFile is only detected for qcow2.
+type DiskInfo struct {
+	DiskFormat      string
+	ContainerFormat string
+	VirtualSize     int64
+	BackingFile     bool
+	BackingFileName string
+}
+
+// InspectImageFile detects the format of a local disk image.
+func InspectImageFile(path string) (info DiskInfo, err error) {
+
+	f, err := os.Open(path)
+	if err != nil {
+		return
+	}
+	defer f.Close()
+
+	fi, err := f.Stat()
+	if err != nil {
+		return
+	}
+
+	head := make([]byte, inspectHeaderSize)
+	n, err := io.ReadFull(f, head)
+	if err != nil && err != io.ErrUnexpectedEOF && err != io.EOF {
+		return
+	}
+	head = head[:n]
+
+	var footer []byte
+	if fi.Size() >= vhdFooterSize {
+		footer = make([]byte, vhdFooterSize)
+		if _, err = f.ReadAt(footer, fi.Size()-vhdFooterSize); err != nil {
+			return
+		}
+	}
+
+	info = inspect(head, footer, fi.Size())
+	err = nil
+	return
+}
+
+// InspectImageReader detects the format of the image data read from r.
+// The header consumed from r is replayed by data, which must be used in
+// place of r afterwards. Fixed size VHDs can not be detected from a stream
+// as their only signature is in the footer, they are reported as raw.
+func InspectImageReader(r io.Reader) (info DiskInfo, data io.Reader, err error) {
+
+	head := make([]byte, inspectHeaderSize)
+	n, err := io.ReadFull(r, head)
+	if err != nil && err != io.ErrUnexpectedEOF && err != io.EOF {
+		return
+	}
+	head = head[:n]
+
+	info = inspect(head, nil, -1)
+	data = io.MultiReader(bytes.NewReader(head), r)
+	err = nil
+	return
+}
+
+func inspect(head []byte, footer []byte, size int64) (info DiskInfo) {
+
+	info = DiskInfo{DiskFormat: "raw", ContainerFormat: "bare"}
+
+	switch {
+	case hasMagic(head, 0, "QFI\xfb") && len(head) >= 32:
+		info.DiskFormat = "qcow2"
+		info.VirtualSize = int64(binary.BigEndian.Uint64(head[24:32]))
+		offset := binary.BigEndian.Uint64(head[8:16])
+		length := binary.BigEndian.Uint32(head[16:20])
+		if offset != 0 {
+			info.BackingFile = true
+			if offset < uint64(len(head)) && uint64(length) <= uint64(len(head))-offset {
+				info.BackingFileName = string(head[offset : offset+uint64(length)])
+			}
+		}
+	case hasMagic(head, 0, "vhdxfile"):
+		info.DiskFormat = "vhdx"
+	case hasMagic(head, 0, "KDMV") && len(head) >= 20:
+		info.DiskFormat = "vmdk"
+		info.VirtualSize = int64(binary.LittleEndian.Uint64(head[12:20])) * 512
+	case hasMagic(head, 0, "# Disk DescriptorFile"):
+		info.DiskFormat = "vmdk"
+	case hasMagic(head, 0, "conectix") && len(head) >= 56:
+		info.DiskFormat = "vhd"
+		info.VirtualSize = int64(binary.BigEndian.Uint64(head[48:56]))
+	case hasMagic(footer, 0, "conectix"):
+		info.DiskFormat = "vhd"
+		info.VirtualSize = int64(binary.BigEndian.Uint64(footer[48:56]))
+	case hasMagic(head, 0x8001, "CD001"):
+		info.DiskFormat = "iso"
+		info.VirtualSize = size
+	case hasMagic(head, 0x202, "HdrS"):
+		// Linux kernel boot protocol header
+		info.DiskFormat = "aki"
+		info.ContainerFormat = "aki"
+	case isRamdisk(head):
+		info.DiskFormat = "ari"
+		info.ContainerFormat = "ari"
+	case hasMagic(head, 1080, "\x53\xef"):
+		// ext2/3/4 superblock without a partition table
+		info.DiskFormat = "ami"
+		info.ContainerFormat = "ami"
+		info.VirtualSize = size
+	default:
+		info.VirtualSize = size
+	}
+
+	if info.VirtualSize < 0 {
+		info.VirtualSize = 0
+	}
+
+	return info
+}
+
+// isRamdisk reports whether head starts a newc cpio archive, as used for
+// initrd, either plain or gzip compressed. Other gzip data is not a ramdisk.
+func isRamdisk(head []byte) bool {
+
+	if hasMagic(head, 0, "\x1f\x8b") {
+		zr, err := gzip.NewReader(bytes.NewReader(head))
+		if err != nil {
+			return false
+		}
+		defer zr.Close()
+
+		magic := make([]byte, 6)
+		if _, err = io.ReadFull(zr, magic); err != nil {
+			return false
+		}
+		head = magic
+	}
+
+	return hasMagic(head, 0, "070701") || hasMagic(head, 0, "070702")
+}
+
+func hasMagic(b []byte, offset int, magic string) bool {
+
+	return len(b) >= offset+len(magic) && string(b[offset:offset+len(magic)]) == magic
+}
+
+// PrepareImage fills the disk and container format of the image record from
+// info, unless already set, and raises MinDisk to the virtual size. qcow2
+// images with a backing file are rejected, the backing file would be read
+// from the compute host.
+func PrepareImage(newImage NewImage, info DiskInfo) (image NewImage, err error) {
+
+	if info.BackingFile {
+		err = errors.New(fmt.Sprintf("qcow2 image has a backing file (%s), flatten it with qemu-img convert before uploading", info.BackingFileName))
+		return
+	}
+
+	image = newImage
+
+	if len(image.DiskFormat) == 0 {
+		image.DiskFormat = info.DiskFormat
+	}
+	if len(image.ContainerFormat) == 0 {
+		image.ContainerFormat = info.ContainerFormat
+	}
+
+	if minDisk := int((info.VirtualSize + gib - 1) / gib); minDisk > image.MinDisk {
+		image.MinDisk = minDisk
+	}
+
+	err = nil
+	return
+}
+
+// CreateImageFromFile inspects the local image, creates the image record
+// accordingly and uploads the file. The image record is deleted again when
+// the upload fails.
+func CreateImageFromFile(auth identity.Auth, path string, newImage NewImage, progress ProgressFunc) (image ImageDetail, err error) {
+
+	info, err := InspectImageFile(path)
+	if err != nil {
+		return
+	}
+
+	if newImage, err = PrepareImage(newImage, info); err != nil {
+		return
+	}
+
+	f, err := os.Open(path)
+	if err != nil {
+		return
+	}
+	defer f.Close()
+
+	fi, err := f.Stat()
+	if err != nil {
+		return
+	}
+
+	if image, err = CreateImage(auth, newImage); err != nil {
+		return
+	}
+
+	if err = UploadImageData(auth, image.Id, f, fi.Size(), progress); err != nil {
+		// the queued record is useless without data, the upload error is
+		// returned whether or not it could be removed
+		if newImage.Protected {
+			UpdateImage(auth, image.Id, []PatchOp{{"replace", "/protected", false}})
+		}
+		imageReq(auth, "DELETE", fmt.Sprintf("%s/v2/images/%s", imageEndpoint(auth), image.Id))
+		image = ImageDetail{}
+		return
+	}
+
+	return GetImageById(auth, image.Id)
+}
@@ -0,0 +1,169 @@
+package image
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/binary"
+	"testing"
+)
+
+// fixture returns a header of size bytes with magic written at offset.
+func fixture(size int, offset int, magic string) []byte {
+
+	b := make([]byte, size)
+	copy(b[offset:], magic)
+
+	return b
+}
+
+func gzipped(data []byte) []byte {
+
+	var buf bytes.Buffer
+	zw := gzip.NewWriter(&buf)
+	zw.Write(data)
+	zw.Close()
+
+	return buf.Bytes()
+}
+
+func TestInspect(t *testing.T) {
+
+	qcow2 := fixture(512, 0, "QFI\xfb")
+	binary.BigEndian.PutUint64(qcow2[24:32], 10*gib)
+
+	qcow2Backing := fixture(512, 0, "QFI\xfb")
+	binary.BigEndian.PutUint64(qcow2Backing[8:16], 256)
+	binary.BigEndian.PutUint32(qcow2Backing[16:20], 8)
+	copy(qcow2Backing[256:], "base.img")
+
+	// the backing file name lies beyond the header that was read
+	qcow2BackingFar := fixture(512, 0, "QFI\xfb")
+	binary.BigEndian.PutUint64(qcow2BackingFar[8:16], 4096)
+	binary.BigEndian.PutUint32(qcow2BackingFar[16:20], 8)
+
+	vmdk := fixture(512, 0, "KDMV")
+	binary.LittleEndian.PutUint64(vmdk[12:20], 2048)
+
+	vhd := fixture(512, 0, "conectix")
+	binary.BigEndian.PutUint64(vhd[48:56], 2*gib)
+
+	vhdFooter := fixture(vhdFooterSize, 0, "conectix")
+	binary.BigEndian.PutUint64(vhdFooter[48:56], 3*gib)
+
+	tests := []struct {
+		name   string
+		head   []byte
+		footer []byte
+		size   int64
+		want   DiskInfo
+	}{
+		{
+			name: "qcow2",
+			head: qcow2,
+			size: 1024,
+			want: DiskInfo{DiskFormat: "qcow2", ContainerFormat: "bare", VirtualSize: 10 * gib},
+		},
+		{
+			name: "qcow2 with backing file",
+			head: qcow2Backing,
+			want: DiskInfo{DiskFormat: "qcow2", ContainerFormat: "bare", BackingFile: true, BackingFileName: "base.img"},
+		},
+		{
+			name: "qcow2 with backing file name out of the header",
+			head: qcow2BackingFar,
+			want: DiskInfo{DiskFormat: "qcow2", ContainerFormat: "bare", BackingFile: true},
+		},
+		{
+			name: "truncated qcow2 header",
+			head: []byte("QFI\xfb\x00\x00\x00\x03"),
+			size: 8,
+			want: DiskInfo{DiskFormat: "raw", ContainerFormat: "bare", VirtualSize: 8},
+		},
+		{
+			name: "vmdk sparse extent",
+			head: vmdk,
+			want: DiskInfo{DiskFormat: "vmdk", ContainerFormat: "bare", VirtualSize: 2048 * 512},
+		},
+		{
+			name: "vmdk descriptor",
+			head: fixture(512, 0, "# Disk DescriptorFile\n"),
+			want: DiskInfo{DiskFormat: "vmdk", ContainerFormat: "bare"},
+		},
+		{
+			name: "vhdx",
+			head: fixture(512, 0, "vhdxfile"),
+			want: DiskInfo{DiskFormat: "vhdx", ContainerFormat: "bare"},
+		},
+		{
+			name: "dynamic vhd",
+			head: vhd,
+			want: DiskInfo{DiskFormat: "vhd", ContainerFormat: "bare", VirtualSize: 2 * gib},
+		},
+		{
+			name:   "fixed vhd",
+			head:   make([]byte, 512),
+			footer: vhdFooter,
+			size:   3*gib + vhdFooterSize,
+			want:   DiskInfo{DiskFormat: "vhd", ContainerFormat: "bare", VirtualSize: 3 * gib},
+		},
+		{
+			name: "iso",
+			head: fixture(inspectHeaderSize, 0x8001, "CD001"),
+			size: 700 * 1024 * 1024,
+			want: DiskInfo{DiskFormat: "iso", ContainerFormat: "bare", VirtualSize: 700 * 1024 * 1024},
+		},
+		{
+			name: "iso signature beyond a short header",
+			head: fixture(0x8003, 0x8001, "CD"),
+			size: 0x8003,
+			want: DiskInfo{DiskFormat: "raw", ContainerFormat: "bare", VirtualSize: 0x8003},
+		},
+		{
+			name: "kernel",
+			head: fixture(1024, 0x202, "HdrS"),
+			want: DiskInfo{DiskFormat: "aki", ContainerFormat: "aki"},
+		},
+		{
+			name: "cpio ramdisk",
+			head: fixture(512, 0, "070701"),
+			want: DiskInfo{DiskFormat: "ari", ContainerFormat: "ari"},
+		},
+		{
+			name: "gzip compressed cpio ramdisk",
+			head: gzipped(fixture(512, 0, "070702")),
+			want: DiskInfo{DiskFormat: "ari", ContainerFormat: "ari"},
+		},
+		{
+			name: "gzip data is not a ramdisk",
+			head: gzipped(fixture(512, 0, "not an archive")),
+			size: 100,
+			want: DiskInfo{DiskFormat: "raw", ContainerFormat: "bare", VirtualSize: 100},
+		},
+		{
+			name: "ext filesystem",
+			head: fixture(2048, 1080, "\x53\xef"),
+			size: gib,
+			want: DiskInfo{DiskFormat: "ami", ContainerFormat: "ami", VirtualSize: gib},
+		},
+		{
+			name: "raw",
+			head: make([]byte, 2048),
+			size: gib,
+			want: DiskInfo{DiskFormat: "raw", ContainerFormat: "bare", VirtualSize: gib},
+		},
+		{
+			name: "raw stream of unknown size",
+			head: make([]byte, 2048),
+			size: -1,
+			want: DiskInfo{DiskFormat: "raw", ContainerFormat: "bare"},
+		},
+	}
+
+	for _, tt := range tests {
+		info := inspect(tt.head, tt.footer, tt.size)
+
+		if info != tt.want {
+			t.Errorf("%s: got %+v, want %+v", tt.name, info, tt.want)
+		}
+	}
+}
@@ -0,0 +1,236 @@
+package image
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/gertd/go-openstack"
+	"github.com/gertd/go-openstack/identity"
+
+	"github.com/parnurzeal/gorequest"
+)
+
+const (
+	MemberStatusAccepted = "accepted"
+	MemberStatusRejected = "rejected"
+	MemberStatusPending  = "pending"
+)
+
+type membersResp struct {
+	Members []Member `json:"members"`
+}
+
+type memberReq struct {
+	Member string `json:"member"`
+}
+
+type memberStatusReq struct {
+	Status string `json:"status"`
+}
+
+// Member is a project an image is shared with, MemberId is the project ID.
+type Member struct {
+	ImageId   string `json:"image_id"`
+	MemberId  string `json:"member_id"`
+	Status    string `json:"status"`
+	CreatedAt string `json:"created_at"`
+	UpdatedAt string `json:"updated_at"`
+	Schema    string `json:"schema"`
+}
+
+func GetImageMembers(auth identity.Auth, id string) (members []Member, err error) {
+
+	req := gorequest.New()
+
+	url := fmt.Sprintf("%s/v2/images/%s/members",
+		imageEndpoint(auth),
+		id)
+
+	resp, body, errs := req.Get(url).
+		Set("Content-Type", "application/json").
+		Set("Accept", "application/json").
+		Set("X-Auth-Token", auth.Access.Token.Id).
+		End()
+
+	if errs != nil {
+		err = errs[len(errs)-1]
+		return
+	}
+
+	if err = openstack.CheckHttpResponseStatusCode(resp.StatusCode); err != nil {
+		return
+	}
+
+	var r = membersResp{}
+	if err = json.Unmarshal([]byte(body), &r); err != nil {
+		return
+	}
+
+	members = r.Members
+	err = nil
+	return
+}
+
+func GetImageMember(auth identity.Auth, id string, memberId string) (member Member, err error) {
+
+	req := gorequest.New()
+
+	url := fmt.Sprintf("%s/v2/images/%s/members/%s",
+		imageEndpoint(auth),
+		id,
+		memberId)
+
+	resp, body, errs := req.Get(url).
+		Set("Content-Type", "application/json").
+		Set("Accept", "application/json").
+		Set("X-Auth-Token", auth.Access.Token.Id).
+		End()
+
+	if errs != nil {
+		err = errs[len(errs)-1]
+		return
+	}
+
+	if err = openstack.CheckHttpResponseStatusCode(resp.StatusCode); err != nil {
+		return
+	}
+
+	if err = json.Unmarshal([]byte(body), &member); err != nil {
+		return
+	}
+
+	err = nil
+	return
+}
+
+// AddImageMember shares the image with the project, the image visibility
+// must be shared. The member starts pending until the project accepts it.
+func AddImageMember(auth identity.Auth, id string, memberId string) (member Member, err error) {
+
+	req := gorequest.New()
+
+	url := fmt.Sprintf("%s/v2/images/%s/members",
+		imageEndpoint(auth),
+		id)
+
+	b, err := json.Marshal(memberReq{memberId})
+	if err != nil {
+		return
+	}
+
+	resp, body, errs := req.Post(url).
+		Set("Content-Type", "application/json").
+		Set("Accept", "application/json").
+		Set("X-Auth-Token", auth.Access.Token.Id).
+		Send(string(b)).
+		End()
+
+	if errs != nil {
+		err = errs[len(errs)-1]
+		return
+	}
+
+	if err = openstack.CheckHttpResponseStatusCode(resp.StatusCode); err != nil {
+		return
+	}
+
+	if err = json.Unmarshal([]byte(body), &member); err != nil {
+		return
+	}
+
+	err = nil
+	return
+}
+
+func DeleteImageMember(auth identity.Auth, id string, memberId string) (err error) {
+
+	return imageReq(auth, "DELETE", fmt.Sprintf("%s/v2/images/%s/members/%s", imageEndpoint(auth), id, memberId))
+}
+
+// UpdateImageMemberStatus accepts or rejects a shared image, it must be
+// called by the member project.
+func UpdateImageMemberStatus(auth identity.Auth, id string, memberId string, status string) (member Member, err error) {
+
+	req := gorequest.New()
+
+	url := fmt.Sprintf("%s/v2/images/%s/members/%s",
+		imageEndpoint(auth),
+		id,
+		memberId)
+
+	b, err := json.Marshal(memberStatusReq{status})
+	if err != nil {
+		return
+	}
+
+	resp, body, errs := req.Put(url).
+		Set("Content-Type", "application/json").
+		Set("Accept", "application/json").
+		Set("X-Auth-Token", auth.Access.Token.Id).
+		Send(string(b)).
+		End()
+
+	if errs != nil {
+		err = errs[len(errs)-1]
+		return
+	}
+
+	if err = openstack.CheckHttpResponseStatusCode(resp.StatusCode); err != nil {
+		return
+	}
+
+	if err = json.Unmarshal([]byte(body), &member); err != nil {
+		return
+	}
+
+	err = nil
+	return
+}
+
+// ShareImage makes sure the image is shared with every project, switching
+// a private image to shared visibility first. Projects already members are
+// left alone, the projects that were added are returned.
+func ShareImage(auth identity.Auth, id string, projectIds []string) (added []string, err error) {
+
+	image, err := GetImageById(auth, id)
+	if err != nil {
+		return
+	}
+
+	switch image.Visibility {
+	case VisibilityShared:
+	case VisibilityPrivate:
+		visibility := VisibilityShared
+		if _, err = UpdateImage(auth, id, ImagePatch(image, ImageUpdate{Visibility: &visibility})); err != nil {
+			return
+		}
+	default:
+		err = errors.New(fmt.Sprintf("image %s has %s visibility and cannot have members", id, image.Visibility))
+		return
+	}
+
+	members, err := GetImageMembers(auth, id)
+	if err != nil {
+		return
+	}
+
+	existing := map[string]bool{}
+	for _, v := range members {
+		existing[v.MemberId] = true
+	}
+
+	for _, v := range projectIds {
+		if existing[v] {
+			continue
+		}
+		if _, err = AddImageMember(auth, id, v); err != nil {
+			return
+		}
+		existing[v] = true
+		added = append(added, v)
+	}
+
+	err = nil
+	return
+}
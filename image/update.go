@@ -0,0 +1,224 @@
+package image
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/gertd/go-openstack"
+	"github.com/gertd/go-openstack/identity"
+
+	"github.com/parnurzeal/gorequest"
+)
+
+const jsonPatchMediaType = "application/openstack-images-v2.1-json-patch"
+
+type PatchOp struct {
+	Op    string      `json:"op"`
+	Path  string      `json:"path"`
+	Value interface{} `json:"value,omitempty"`
+}
+
+// ImageUpdate is the desired state of an image, nil fields are left
+// untouched. A non nil Properties is the complete set of custom properties:
+// properties of the image missing from it are removed.
+type ImageUpdate struct {
+	Name            *string
+	Visibility      *string
+	Protected       *bool
+	Hidden          *bool
+	MinDisk         *int
+	MinRam          *int
+	DiskFormat      *string
+	ContainerFormat *string
+	Properties      map[string]string
+}
+
+// ImagePatch computes the JSON Patch operations turning current into
+// desired.
+func ImagePatch(current ImageDetail, desired ImageUpdate) (ops []PatchOp) {
+
+	if desired.Name != nil && *desired.Name != current.Name {
+		ops = append(ops, PatchOp{"replace", "/name", *desired.Name})
+	}
+	if desired.Visibility != nil && *desired.Visibility != current.Visibility {
+		ops = append(ops, PatchOp{"replace", "/visibility", *desired.Visibility})
+	}
+	if desired.Protected != nil && *desired.Protected != current.Protected {
+		ops = append(ops, PatchOp{"replace", "/protected", *desired.Protected})
+	}
+	if desired.Hidden != nil && *desired.Hidden != current.Hidden {
+		ops = append(ops, PatchOp{"replace", "/os_hidden", *desired.Hidden})
+	}
+	if desired.MinDisk != nil && *desired.MinDisk != current.MinDisk {
+		ops = append(ops, PatchOp{"replace", "/min_disk", *desired.MinDisk})
+	}
+	if desired.MinRam != nil && *desired.MinRam != current.MinRam {
+		ops = append(ops, PatchOp{"replace", "/min_ram", *desired.MinRam})
+	}
+	if desired.DiskFormat != nil && *desired.DiskFormat != current.DiskFormat {
+		ops = append(ops, PatchOp{"replace", "/disk_format", *desired.DiskFormat})
+	}
+	if desired.ContainerFormat != nil && *desired.ContainerFormat != current.ContainerFormat {
+		ops = append(ops, PatchOp{"replace", "/container_format", *desired.ContainerFormat})
+	}
+
+	if desired.Properties == nil {
+		return ops
+	}
+
+	keys := make([]string, 0, len(desired.Properties))
+	for k := range desired.Properties {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	for _, k := range keys {
+		v := desired.Properties[k]
+		if old, ok := current.Properties[k]; !ok {
+			ops = append(ops, PatchOp{"add", propertyPath(k), v})
+		} else if old != v {
+			ops = append(ops, PatchOp{"replace", propertyPath(k), v})
+		}
+	}
+
+	keys = keys[:0]
+	for k := range current.Properties {
+		if _, ok := desired.Properties[k]; !ok {
+			keys = append(keys, k)
+		}
+	}
+	sort.Strings(keys)
+
+	for _, k := range keys {
+		ops = append(ops, PatchOp{Op: "remove", Path: propertyPath(k)})
+	}
+
+	return ops
+}
+
+// propertyPath escapes the property name as a JSON pointer.
+func propertyPath(name string) string {
+
+	return "/" + strings.Replace(strings.Replace(name, "~", "~0", -1), "/", "~1", -1)
+}
+
+func UpdateImage(auth identity.Auth, id string, ops []PatchOp) (image ImageDetail, err error) {
+
+	if len(ops) == 0 {
+		return GetImageById(auth, id)
+	}
+
+	req := gorequest.New()
+
+	url := fmt.Sprintf("%s/v2/images/%s",
+		imageEndpoint(auth),
+		id)
+
+	b, err := json.Marshal(ops)
+	if err != nil {
+		return
+	}
+
+	resp, body, errs := req.Patch(url).
+		Set("Content-Type", jsonPatchMediaType).
+		Set("Accept", "application/json").
+		Set("X-Auth-Token", auth.Access.Token.Id).
+		Send(string(b)).
+		End()
+
+	if errs != nil {
+		err = errs[len(errs)-1]
+		return
+	}
+
+	if err = openstack.CheckHttpResponseStatusCode(resp.StatusCode); err != nil {
+		return
+	}
+
+	if err = json.Unmarshal([]byte(body), &image); err != nil {
+		return
+	}
+
+	err = nil
+	return
+}
+
+// ApplyImageUpdate brings the image to the desired state with a single
+// PATCH request and returns the operations that were applied.
+func ApplyImageUpdate(auth identity.Auth, id string, desired ImageUpdate) (image ImageDetail, ops []PatchOp, err error) {
+
+	current, err := GetImageById(auth, id)
+	if err != nil {
+		return
+	}
+
+	ops = ImagePatch(current, desired)
+
+	image, err = UpdateImage(auth, id, ops)
+	return
+}
+
+func AddImageTag(auth identity.Auth, id string, tag string) (err error) {
+
+	return imageReq(auth, "PUT", fmt.Sprintf("%s/v2/images/%s/tags/%s", imageEndpoint(auth), id, tag))
+}
+
+func DeleteImageTag(auth identity.Auth, id string, tag string) (err error) {
+
+	return imageReq(auth, "DELETE", fmt.Sprintf("%s/v2/images/%s/tags/%s", imageEndpoint(auth), id, tag))
+}
+
+// DeactivateImage makes the image data unavailable to non admin users
+// while keeping the record, e.g. while investigating a compromised image.
+func DeactivateImage(auth identity.Auth, id string) (err error) {
+
+	return imageReq(auth, "POST", fmt.Sprintf("%s/v2/images/%s/actions/deactivate", imageEndpoint(auth), id))
+}
+
+func ReactivateImage(auth identity.Auth, id string) (err error) {
+
+	return imageReq(auth, "POST", fmt.Sprintf("%s/v2/images/%s/actions/reactivate", imageEndpoint(auth), id))
+}
+
+// DeleteImage refuses to delete protected images, Glance answers 403 for
+// them which is indistinguishable from a permission problem.
+func DeleteImage(auth identity.Auth, id string) (err error) {
+
+	image, err := GetImageById(auth, id)
+	if err != nil {
+		return
+	}
+
+	if image.Protected {
+		err = errors.New(fmt.Sprintf("image %s (%s) is protected, set protected to false before deleting it", id, image.Name))
+		return
+	}
+
+	return imageReq(auth, "DELETE", fmt.Sprintf("%s/v2/images/%s", imageEndpoint(auth), id))
+}
+
+func imageReq(auth identity.Auth, method string, url string) (err error) {
+
+	req := gorequest.New()
+
+	resp, _, errs := req.CustomMethod(method, url).
+		Set("Content-Type", "application/json").
+		Set("Accept", "application/json").
+		Set("X-Auth-Token", auth.Access.Token.Id).
+		End()
+
+	if errs != nil {
+		err = errs[len(errs)-1]
+		return
+	}
+
+	if err = openstack.CheckHttpResponseStatusCode(resp.StatusCode); err != nil {
+		return
+	}
+
+	err = nil
+	return
+}
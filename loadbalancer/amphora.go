@@ -0,0 +1,180 @@
+package loadbalancer
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/gertd/go-openstack"
+	"github.com/gertd/go-openstack/identity"
+
+	"github.com/parnurzeal/gorequest"
+)
+
+type amphoraeResp struct {
+	Amphorae []Amphora `json:"amphorae"`
+}
+
+type amphoraResp struct {
+	Amphora Amphora `json:"amphora"`
+}
+
+type providersResp struct {
+	Providers []Provider `json:"providers"`
+}
+
+// The amphora API is only available to admins.
+type Amphora struct {
+	Id             string `json:"id"`
+	LoadBalancerId string `json:"loadbalancer_id"`
+	ComputeId      string `json:"compute_id"`
+	Status         string `json:"status"`
+	Role           string `json:"role"`
+	LBNetworkIP    string `json:"lb_network_ip"`
+	VRRPIP         string `json:"vrrp_ip"`
+	HAIP           string `json:"ha_ip"`
+	VRRPPortId     string `json:"vrrp_port_id"`
+	HAPortId       string `json:"ha_port_id"`
+	VRRPInterface  string `json:"vrrp_interface"`
+	VRRPPriority   int    `json:"vrrp_priority"`
+	CertExpiration string `json:"cert_expiration"`
+	CertBusy       bool   `json:"cert_busy"`
+	ComputeFlavor  string `json:"compute_flavor"`
+	ImageId        string `json:"image_id"`
+	CachedZone     string `json:"cached_zone"`
+	CreatedAt      string `json:"created_at"`
+	UpdatedAt      string `json:"updated_at"`
+}
+
+type Provider struct {
+	Name        string `json:"name"`
+	Description string `json:"description"`
+}
+
+// GetAmphorae returns the amphorae of the load balancer, or every amphora
+// when loadBalancerId is empty.
+func GetAmphorae(auth identity.Auth, loadBalancerId string) (amphorae []Amphora, err error) {
+
+	req := gorequest.New()
+
+	url := fmt.Sprintf("%s/v2/octavia/amphorae",
+		loadBalancerEndpoint(auth))
+	if loadBalancerId != "" {
+		url += "?loadbalancer_id=" + loadBalancerId
+	}
+
+	resp, body, errs := req.Get(url).
+		Set("Content-Type", "application/json").
+		Set("Accept", "application/json").
+		Set("X-Auth-Token", auth.Access.Token.Id).
+		End()
+
+	if errs != nil {
+		err = errs[len(errs)-1]
+		return
+	}
+
+	if err = openstack.CheckHttpResponseStatusCode(resp.StatusCode); err != nil {
+		return
+	}
+
+	var r = amphoraeResp{}
+	if err = json.Unmarshal([]byte(body), &r); err != nil {
+		return
+	}
+
+	amphorae = r.Amphorae
+	err = nil
+	return
+}
+
+func GetAmphora(auth identity.Auth, id string) (amphora Amphora, err error) {
+
+	req := gorequest.New()
+
+	url := fmt.Sprintf("%s/v2/octavia/amphorae/%s",
+		loadBalancerEndpoint(auth),
+		id)
+
+	resp, body, errs := req.Get(url).
+		Set("Content-Type", "application/json").
+		Set("Accept", "application/json").
+		Set("X-Auth-Token", auth.Access.Token.Id).
+		End()
+
+	if errs != nil {
+		err = errs[len(errs)-1]
+		return
+	}
+
+	if err = openstack.CheckHttpResponseStatusCode(resp.StatusCode); err != nil {
+		return
+	}
+
+	var r = amphoraResp{}
+	if err = json.Unmarshal([]byte(body), &r); err != nil {
+		return
+	}
+
+	amphora = r.Amphora
+	err = nil
+	return
+}
+
+func FailoverAmphora(auth identity.Auth, id string) (err error) {
+
+	req := gorequest.New()
+
+	url := fmt.Sprintf("%s/v2/octavia/amphorae/%s/failover",
+		loadBalancerEndpoint(auth),
+		id)
+
+	resp, _, errs := req.Put(url).
+		Set("Content-Type", "application/json").
+		Set("Accept", "application/json").
+		Set("X-Auth-Token", auth.Access.Token.Id).
+		End()
+
+	if errs != nil {
+		err = errs[len(errs)-1]
+		return
+	}
+
+	if err = openstack.CheckHttpResponseStatusCode(resp.StatusCode); err != nil {
+		return
+	}
+
+	err = nil
+	return
+}
+
+func GetProviders(auth identity.Auth) (providers []Provider, err error) {
+
+	req := gorequest.New()
+
+	url := fmt.Sprintf("%s/v2/lbaas/providers",
+		loadBalancerEndpoint(auth))
+
+	resp, body, errs := req.Get(url).
+		Set("Content-Type", "application/json").
+		Set("Accept", "application/json").
+		Set("X-Auth-Token", auth.Access.Token.Id).
+		End()
+
+	if errs != nil {
+		err = errs[len(errs)-1]
+		return
+	}
+
+	if err = openstack.CheckHttpResponseStatusCode(resp.StatusCode); err != nil {
+		return
+	}
+
+	var r = providersResp{}
+	if err = json.Unmarshal([]byte(body), &r); err != nil {
+		return
+	}
+
+	providers = r.Providers
+	err = nil
+	return
+}
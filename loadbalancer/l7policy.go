@@ -0,0 +1,447 @@
+package loadbalancer
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/gertd/go-openstack"
+	"github.com/gertd/go-openstack/identity"
+
+	"github.com/parnurzeal/gorequest"
+)
+
+type l7PoliciesResp struct {
+	L7Policies []L7Policy `json:"l7policies"`
+}
+
+type l7PolicyResp struct {
+	L7Policy L7Policy `json:"l7policy"`
+}
+
+type l7PolicyReq struct {
+	L7Policy L7PolicyOpts `json:"l7policy"`
+}
+
+type l7RulesResp struct {
+	Rules []L7Rule `json:"rules"`
+}
+
+type l7RuleResp struct {
+	Rule L7Rule `json:"rule"`
+}
+
+type l7RuleReq struct {
+	Rule L7RuleOpts `json:"rule"`
+}
+
+// Action is one of REDIRECT_TO_POOL, REDIRECT_TO_URL, REDIRECT_PREFIX and
+// REJECT, the matching Redirect* field holds the target.
+type L7Policy struct {
+	Id                 string   `json:"id"`
+	Name               string   `json:"name"`
+	Description        string   `json:"description"`
+	ListenerId         string   `json:"listener_id"`
+	Action             string   `json:"action"`
+	Position           int      `json:"position"`
+	RedirectPoolId     string   `json:"redirect_pool_id"`
+	RedirectURL        string   `json:"redirect_url"`
+	RedirectPrefix     string   `json:"redirect_prefix"`
+	RedirectHTTPCode   int      `json:"redirect_http_code"`
+	AdminStateUp       bool     `json:"admin_state_up"`
+	Rules              []IdRef  `json:"rules"`
+	ProvisioningStatus string   `json:"provisioning_status"`
+	OperatingStatus    string   `json:"operating_status"`
+	ProjectId          string   `json:"project_id"`
+	Tags               []string `json:"tags"`
+	CreatedAt          string   `json:"created_at"`
+	UpdatedAt          string   `json:"updated_at"`
+}
+
+// L7PolicyOpts is used to create and update L7 policies, nil and empty
+// fields are not sent.
+type L7PolicyOpts struct {
+	ListenerId       string   `json:"listener_id,omitempty"`
+	Name             *string  `json:"name,omitempty"`
+	Description      *string  `json:"description,omitempty"`
+	Action           string   `json:"action,omitempty"`
+	Position         int      `json:"position,omitempty"`
+	RedirectPoolId   *string  `json:"redirect_pool_id,omitempty"`
+	RedirectURL      *string  `json:"redirect_url,omitempty"`
+	RedirectPrefix   *string  `json:"redirect_prefix,omitempty"`
+	RedirectHTTPCode int      `json:"redirect_http_code,omitempty"`
+	AdminStateUp     *bool    `json:"admin_state_up,omitempty"`
+	Tags             []string `json:"tags,omitempty"`
+}
+
+// Type is one of HOST_NAME, PATH, FILE_TYPE, HEADER, COOKIE and the
+// SSL_* types, Key names the header or cookie.
+type L7Rule struct {
+	Id                 string   `json:"id"`
+	Type               string   `json:"type"`
+	CompareType        string   `json:"compare_type"`
+	Key                string   `json:"key"`
+	Value              string   `json:"value"`
+	Invert             bool     `json:"invert"`
+	AdminStateUp       bool     `json:"admin_state_up"`
+	ProvisioningStatus string   `json:"provisioning_status"`
+	OperatingStatus    string   `json:"operating_status"`
+	ProjectId          string   `json:"project_id"`
+	Tags               []string `json:"tags"`
+	CreatedAt          string   `json:"created_at"`
+	UpdatedAt          string   `json:"updated_at"`
+}
+
+// L7RuleOpts is used to create and update L7 rules, nil and empty fields
+// are not sent.
+type L7RuleOpts struct {
+	Type         string   `json:"type,omitempty"`
+	CompareType  string   `json:"compare_type,omitempty"`
+	Key          *string  `json:"key,omitempty"`
+	Value        string   `json:"value,omitempty"`
+	Invert       *bool    `json:"invert,omitempty"`
+	AdminStateUp *bool    `json:"admin_state_up,omitempty"`
+	Tags         []string `json:"tags,omitempty"`
+}
+
+func GetL7Policies(auth identity.Auth) (l7Policies []L7Policy, err error) {
+
+	req := gorequest.New()
+
+	url := fmt.Sprintf("%s/v2/lbaas/l7policies",
+		loadBalancerEndpoint(auth))
+
+	resp, body, errs := req.Get(url).
+		Set("Content-Type", "application/json").
+		Set("Accept", "application/json").
+		Set("X-Auth-Token", auth.Access.Token.Id).
+		End()
+
+	if errs != nil {
+		err = errs[len(errs)-1]
+		return
+	}
+
+	if err = openstack.CheckHttpResponseStatusCode(resp.StatusCode); err != nil {
+		return
+	}
+
+	var r = l7PoliciesResp{}
+	if err = json.Unmarshal([]byte(body), &r); err != nil {
+		return
+	}
+
+	l7Policies = r.L7Policies
+	err = nil
+	return
+}
+
+func GetL7Policy(auth identity.Auth, id string) (l7Policy L7Policy, err error) {
+
+	req := gorequest.New()
+
+	url := fmt.Sprintf("%s/v2/lbaas/l7policies/%s",
+		loadBalancerEndpoint(auth),
+		id)
+
+	resp, body, errs := req.Get(url).
+		Set("Content-Type", "application/json").
+		Set("Accept", "application/json").
+		Set("X-Auth-Token", auth.Access.Token.Id).
+		End()
+
+	if errs != nil {
+		err = errs[len(errs)-1]
+		return
+	}
+
+	if err = openstack.CheckHttpResponseStatusCode(resp.StatusCode); err != nil {
+		return
+	}
+
+	var r = l7PolicyResp{}
+	if err = json.Unmarshal([]byte(body), &r); err != nil {
+		return
+	}
+
+	l7Policy = r.L7Policy
+	err = nil
+	return
+}
+
+func CreateL7Policy(auth identity.Auth, opts L7PolicyOpts) (l7Policy L7Policy, err error) {
+
+	req := gorequest.New()
+
+	url := fmt.Sprintf("%s/v2/lbaas/l7policies",
+		loadBalancerEndpoint(auth))
+
+	b, err := json.Marshal(l7PolicyReq{opts})
+	if err != nil {
+		return
+	}
+
+	resp, body, errs := req.Post(url).
+		Set("Content-Type", "application/json").
+		Set("Accept", "application/json").
+		Set("X-Auth-Token", auth.Access.Token.Id).
+		Send(string(b)).
+		End()
+
+	if errs != nil {
+		err = errs[len(errs)-1]
+		return
+	}
+
+	if err = openstack.CheckHttpResponseStatusCode(resp.StatusCode); err != nil {
+		return
+	}
+
+	var r = l7PolicyResp{}
+	if err = json.Unmarshal([]byte(body), &r); err != nil {
+		return
+	}
+
+	l7Policy = r.L7Policy
+	err = nil
+	return
+}
+
+func UpdateL7Policy(auth identity.Auth, id string, opts L7PolicyOpts) (l7Policy L7Policy, err error) {
+
+	req := gorequest.New()
+
+	url := fmt.Sprintf("%s/v2/lbaas/l7policies/%s",
+		loadBalancerEndpoint(auth),
+		id)
+
+	b, err := json.Marshal(l7PolicyReq{opts})
+	if err != nil {
+		return
+	}
+
+	resp, body, errs := req.Put(url).
+		Set("Content-Type", "application/json").
+		Set("Accept", "application/json").
+		Set("X-Auth-Token", auth.Access.Token.Id).
+		Send(string(b)).
+		End()
+
+	if errs != nil {
+		err = errs[len(errs)-1]
+		return
+	}
+
+	if err = openstack.CheckHttpResponseStatusCode(resp.StatusCode); err != nil {
+		return
+	}
+
+	var r = l7PolicyResp{}
+	if err = json.Unmarshal([]byte(body), &r); err != nil {
+		return
+	}
+
+	l7Policy = r.L7Policy
+	err = nil
+	return
+}
+
+func DeleteL7Policy(auth identity.Auth, id string) (err error) {
+
+	req := gorequest.New()
+
+	url := fmt.Sprintf("%s/v2/lbaas/l7policies/%s",
+		loadBalancerEndpoint(auth),
+		id)
+
+	resp, _, errs := req.Delete(url).
+		Set("Content-Type", "application/json").
+		Set("Accept", "application/json").
+		Set("X-Auth-Token", auth.Access.Token.Id).
+		End()
+
+	if errs != nil {
+		err = errs[len(errs)-1]
+		return
+	}
+
+	if err = openstack.CheckHttpResponseStatusCode(resp.StatusCode); err != nil {
+		return
+	}
+
+	err = nil
+	return
+}
+
+func GetL7Rules(auth identity.Auth, policyId string) (rules []L7Rule, err error) {
+
+	req := gorequest.New()
+
+	url := fmt.Sprintf("%s/v2/lbaas/l7policies/%s/rules",
+		loadBalancerEndpoint(auth),
+		policyId)
+
+	resp, body, errs := req.Get(url).
+		Set("Content-Type", "application/json").
+		Set("Accept", "application/json").
+		Set("X-Auth-Token", auth.Access.Token.Id).
+		End()
+
+	if errs != nil {
+		err = errs[len(errs)-1]
+		return
+	}
+
+	if err = openstack.CheckHttpResponseStatusCode(resp.StatusCode); err != nil {
+		return
+	}
+
+	var r = l7RulesResp{}
+	if err = json.Unmarshal([]byte(body), &r); err != nil {
+		return
+	}
+
+	rules = r.Rules
+	err = nil
+	return
+}
+
+func GetL7Rule(auth identity.Auth, policyId string, id string) (rule L7Rule, err error) {
+
+	req := gorequest.New()
+
+	url := fmt.Sprintf("%s/v2/lbaas/l7policies/%s/rules/%s",
+		loadBalancerEndpoint(auth),
+		policyId,
+		id)
+
+	resp, body, errs := req.Get(url).
+		Set("Content-Type", "application/json").
+		Set("Accept", "application/json").
+		Set("X-Auth-Token", auth.Access.Token.Id).
+		End()
+
+	if errs != nil {
+		err = errs[len(errs)-1]
+		return
+	}
+
+	if err = openstack.CheckHttpResponseStatusCode(resp.StatusCode); err != nil {
+		return
+	}
+
+	var r = l7RuleResp{}
+	if err = json.Unmarshal([]byte(body), &r); err != nil {
+		return
+	}
+
+	rule = r.Rule
+	err = nil
+	return
+}
+
+func CreateL7Rule(auth identity.Auth, policyId string, opts L7RuleOpts) (rule L7Rule, err error) {
+
+	req := gorequest.New()
+
+	url := fmt.Sprintf("%s/v2/lbaas/l7policies/%s/rules",
+		loadBalancerEndpoint(auth),
+		policyId)
+
+	b, err := json.Marshal(l7RuleReq{opts})
+	if err != nil {
+		return
+	}
+
+	resp, body, errs := req.Post(url).
+		Set("Content-Type", "application/json").
+		Set("Accept", "application/json").
+		Set("X-Auth-Token", auth.Access.Token.Id).
+		Send(string(b)).
+		End()
+
+	if errs != nil {
+		err = errs[len(errs)-1]
+		return
+	}
+
+	if err = openstack.CheckHttpResponseStatusCode(resp.StatusCode); err != nil {
+		return
+	}
+
+	var r = l7RuleResp{}
+	if err = json.Unmarshal([]byte(body), &r); err != nil {
+		return
+	}
+
+	rule = r.Rule
+	err = nil
+	return
+}
+
+func UpdateL7Rule(auth identity.Auth, policyId string, id string, opts L7RuleOpts) (rule L7Rule, err error) {
+
+	req := gorequest.New()
+
+	url := fmt.Sprintf("%s/v2/lbaas/l7policies/%s/rules/%s",
+		loadBalancerEndpoint(auth),
+		policyId,
+		id)
+
+	b, err := json.Marshal(l7RuleReq{opts})
+	if err != nil {
+		return
+	}
+
+	resp, body, errs := req.Put(url).
+		Set("Content-Type", "application/json").
+		Set("Accept", "application/json").
+		Set("X-Auth-Token", auth.Access.Token.Id).
+		Send(string(b)).
+		End()
+
+	if errs != nil {
+		err = errs[len(errs)-1]
+		return
+	}
+
+	if err = openstack.CheckHttpResponseStatusCode(resp.StatusCode); err != nil {
+		return
+	}
+
+	var r = l7RuleResp{}
+	if err = json.Unmarshal([]byte(body), &r); err != nil {
+		return
+	}
+
+	rule = r.Rule
+	err = nil
+	return
+}
+
+func DeleteL7Rule(auth identity.Auth, policyId string, id string) (err error) {
+
+	req := gorequest.New()
+
+	url := fmt.Sprintf("%s/v2/lbaas/l7policies/%s/rules/%s",
+		loadBalancerEndpoint(auth),
+		policyId,
+		id)
+
+	resp, _, errs := req.Delete(url).
+		Set("Content-Type", "application/json").
+		Set("Accept", "application/json").
+		Set("X-Auth-Token", auth.Access.Token.Id).
+		End()
+
+	if errs != nil {
+		err = errs[len(errs)-1]
+		return
+	}
+
+	if err = openstack.CheckHttpResponseStatusCode(resp.StatusCode); err != nil {
+		return
+	}
+
+	err = nil
+	return
+}
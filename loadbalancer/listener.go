@@ -0,0 +1,250 @@
+package loadbalancer
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/gertd/go-openstack"
+	"github.com/gertd/go-openstack/identity"
+
+	"github.com/parnurzeal/gorequest"
+)
+
+type listenersResp struct {
+	Listeners []Listener `json:"listeners"`
+}
+
+type listenerResp struct {
+	Listener Listener `json:"listener"`
+}
+
+type listenerReq struct {
+	Listener ListenerOpts `json:"listener"`
+}
+
+// DefaultTLSContainerRef and SNIContainerRefs are the Barbican references
+// of the certificates used by TERMINATED_HTTPS listeners.
+type Listener struct {
+	Id                      string            `json:"id"`
+	Name                    string            `json:"name"`
+	Description             string            `json:"description"`
+	Protocol                string            `json:"protocol"`
+	ProtocolPort            int               `json:"protocol_port"`
+	ConnectionLimit         int               `json:"connection_limit"`
+	DefaultPoolId           string            `json:"default_pool_id"`
+	DefaultTLSContainerRef  string            `json:"default_tls_container_ref"`
+	SNIContainerRefs        []string          `json:"sni_container_refs"`
+	ClientCATLSContainerRef string            `json:"client_ca_tls_container_ref"`
+	ClientAuthentication    string            `json:"client_authentication"`
+	TLSVersions             []string          `json:"tls_versions"`
+	AllowedCIDRs            []string          `json:"allowed_cidrs"`
+	InsertHeaders           map[string]string `json:"insert_headers"`
+	TimeoutClientData       int               `json:"timeout_client_data"`
+	TimeoutMemberConnect    int               `json:"timeout_member_connect"`
+	TimeoutMemberData       int               `json:"timeout_member_data"`
+	TimeoutTCPInspect       int               `json:"timeout_tcp_inspect"`
+	AdminStateUp            bool              `json:"admin_state_up"`
+	ProvisioningStatus      string            `json:"provisioning_status"`
+	OperatingStatus         string            `json:"operating_status"`
+	LoadBalancers           []IdRef           `json:"loadbalancers"`
+	L7Policies              []IdRef           `json:"l7policies"`
+	ProjectId               string            `json:"project_id"`
+	Tags                    []string          `json:"tags"`
+	CreatedAt               string            `json:"created_at"`
+	UpdatedAt               string            `json:"updated_at"`
+}
+
+// ListenerOpts is used to create and update listeners, nil and empty
+// fields are not sent. LoadBalancerId, Protocol and ProtocolPort can only
+// be set on creation.
+type ListenerOpts struct {
+	LoadBalancerId          string             `json:"loadbalancer_id,omitempty"`
+	Name                    *string            `json:"name,omitempty"`
+	Description             *string            `json:"description,omitempty"`
+	Protocol                string             `json:"protocol,omitempty"`
+	ProtocolPort            int                `json:"protocol_port,omitempty"`
+	ConnectionLimit         *int               `json:"connection_limit,omitempty"`
+	DefaultPoolId           *string            `json:"default_pool_id,omitempty"`
+	DefaultTLSContainerRef  *string            `json:"default_tls_container_ref,omitempty"`
+	SNIContainerRefs        *[]string          `json:"sni_container_refs,omitempty"`
+	ClientCATLSContainerRef *string            `json:"client_ca_tls_container_ref,omitempty"`
+	ClientAuthentication    string             `json:"client_authentication,omitempty"`
+	TLSVersions             *[]string          `json:"tls_versions,omitempty"`
+	AllowedCIDRs            *[]string          `json:"allowed_cidrs,omitempty"`
+	InsertHeaders           *map[string]string `json:"insert_headers,omitempty"`
+	TimeoutClientData       *int               `json:"timeout_client_data,omitempty"`
+	TimeoutMemberConnect    *int               `json:"timeout_member_connect,omitempty"`
+	TimeoutMemberData       *int               `json:"timeout_member_data,omitempty"`
+	TimeoutTCPInspect       *int               `json:"timeout_tcp_inspect,omitempty"`
+	AdminStateUp            *bool              `json:"admin_state_up,omitempty"`
+	Tags                    []string           `json:"tags,omitempty"`
+}
+
+func GetListeners(auth identity.Auth) (listeners []Listener, err error) {
+
+	req := gorequest.New()
+
+	url := fmt.Sprintf("%s/v2/lbaas/listeners",
+		loadBalancerEndpoint(auth))
+
+	resp, body, errs := req.Get(url).
+		Set("Content-Type", "application/json").
+		Set("Accept", "application/json").
+		Set("X-Auth-Token", auth.Access.Token.Id).
+		End()
+
+	if errs != nil {
+		err = errs[len(errs)-1]
+		return
+	}
+
+	if err = openstack.CheckHttpResponseStatusCode(resp.StatusCode); err != nil {
+		return
+	}
+
+	var r = listenersResp{}
+	if err = json.Unmarshal([]byte(body), &r); err != nil {
+		return
+	}
+
+	listeners = r.Listeners
+	err = nil
+	return
+}
+
+func GetListener(auth identity.Auth, id string) (listener Listener, err error) {
+
+	req := gorequest.New()
+
+	url := fmt.Sprintf("%s/v2/lbaas/listeners/%s",
+		loadBalancerEndpoint(auth),
+		id)
+
+	resp, body, errs := req.Get(url).
+		Set("Content-Type", "application/json").
+		Set("Accept", "application/json").
+		Set("X-Auth-Token", auth.Access.Token.Id).
+		End()
+
+	if errs != nil {
+		err = errs[len(errs)-1]
+		return
+	}
+
+	if err = openstack.CheckHttpResponseStatusCode(resp.StatusCode); err != nil {
+		return
+	}
+
+	var r = listenerResp{}
+	if err = json.Unmarshal([]byte(body), &r); err != nil {
+		return
+	}
+
+	listener = r.Listener
+	err = nil
+	return
+}
+
+func CreateListener(auth identity.Auth, opts ListenerOpts) (listener Listener, err error) {
+
+	req := gorequest.New()
+
+	url := fmt.Sprintf("%s/v2/lbaas/listeners",
+		loadBalancerEndpoint(auth))
+
+	b, err := json.Marshal(listenerReq{opts})
+	if err != nil {
+		return
+	}
+
+	resp, body, errs := req.Post(url).
+		Set("Content-Type", "application/json").
+		Set("Accept", "application/json").
+		Set("X-Auth-Token", auth.Access.Token.Id).
+		Send(string(b)).
+		End()
+
+	if errs != nil {
+		err = errs[len(errs)-1]
+		return
+	}
+
+	if err = openstack.CheckHttpResponseStatusCode(resp.StatusCode); err != nil {
+		return
+	}
+
+	var r = listenerResp{}
+	if err = json.Unmarshal([]byte(body), &r); err != nil {
+		return
+	}
+
+	listener = r.Listener
+	err = nil
+	return
+}
+
+func UpdateListener(auth identity.Auth, id string, opts ListenerOpts) (listener Listener, err error) {
+
+	req := gorequest.New()
+
+	url := fmt.Sprintf("%s/v2/lbaas/listeners/%s",
+		loadBalancerEndpoint(auth),
+		id)
+
+	b, err := json.Marshal(listenerReq{opts})
+	if err != nil {
+		return
+	}
+
+	resp, body, errs := req.Put(url).
+		Set("Content-Type", "application/json").
+		Set("Accept", "application/json").
+		Set("X-Auth-Token", auth.Access.Token.Id).
+		Send(string(b)).
+		End()
+
+	if errs != nil {
+		err = errs[len(errs)-1]
+		return
+	}
+
+	if err = openstack.CheckHttpResponseStatusCode(resp.StatusCode); err != nil {
+		return
+	}
+
+	var r = listenerResp{}
+	if err = json.Unmarshal([]byte(body), &r); err != nil {
+		return
+	}
+
+	listener = r.Listener
+	err = nil
+	return
+}
+
+func DeleteListener(auth identity.Auth, id string) (err error) {
+
+	req := gorequest.New()
+
+	url := fmt.Sprintf("%s/v2/lbaas/listeners/%s",
+		loadBalancerEndpoint(auth),
+		id)
+
+	resp, _, errs := req.Delete(url).
+		Set("Content-Type", "application/json").
+		Set("Accept", "application/json").
+		Set("X-Auth-Token", auth.Access.Token.Id).
+		End()
+
+	if errs != nil {
+		err = errs[len(errs)-1]
+		return
+	}
+
+	if err = openstack.CheckHttpResponseStatusCode(resp.StatusCode); err != nil {
+		return
+	}
+
+	err = nil
+	return
+}
@@ -0,0 +1,375 @@
+// Package loadbalancer is a client for the Octavia load balancer API.
+// Octavia rejects changes to a load balancer and its children while its
+// provisioning_status is PENDING_*, use the WaitFor*Active functions
+// between changes.
+package loadbalancer
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/gertd/go-openstack"
+	"github.com/gertd/go-openstack/identity"
+
+	"github.com/parnurzeal/gorequest"
+)
+
+const (
+	ProvisioningActive        = "ACTIVE"
+	ProvisioningError         = "ERROR"
+	ProvisioningPendingCreate = "PENDING_CREATE"
+	ProvisioningPendingUpdate = "PENDING_UPDATE"
+	ProvisioningPendingDelete = "PENDING_DELETE"
+)
+
+type loadBalancersResp struct {
+	LoadBalancers []LoadBalancer `json:"loadbalancers"`
+}
+
+type loadBalancerResp struct {
+	LoadBalancer LoadBalancer `json:"loadbalancer"`
+}
+
+type loadBalancerReq struct {
+	LoadBalancer LoadBalancerOpts `json:"loadbalancer"`
+}
+
+type statusTreeResp struct {
+	Statuses struct {
+		LoadBalancer LoadBalancerStatus `json:"loadbalancer"`
+	} `json:"statuses"`
+}
+
+type IdRef struct {
+	Id string `json:"id"`
+}
+
+type LoadBalancer struct {
+	Id                 string   `json:"id"`
+	Name               string   `json:"name"`
+	Description        string   `json:"description"`
+	ProvisioningStatus string   `json:"provisioning_status"`
+	OperatingStatus    string   `json:"operating_status"`
+	AdminStateUp       bool     `json:"admin_state_up"`
+	VipAddress         string   `json:"vip_address"`
+	VipPortId          string   `json:"vip_port_id"`
+	VipSubnetId        string   `json:"vip_subnet_id"`
+	VipNetworkId       string   `json:"vip_network_id"`
+	VipQosPolicyId     string   `json:"vip_qos_policy_id"`
+	Listeners          []IdRef  `json:"listeners"`
+	Pools              []IdRef  `json:"pools"`
+	Provider           string   `json:"provider"`
+	FlavorId           string   `json:"flavor_id"`
+	AvailabilityZone   string   `json:"availability_zone"`
+	ProjectId          string   `json:"project_id"`
+	Tags               []string `json:"tags"`
+	CreatedAt          string   `json:"created_at"`
+	UpdatedAt          string   `json:"updated_at"`
+}
+
+// LoadBalancerOpts is used to create and update load balancers, nil and
+// empty fields are not sent. One of the Vip*Id fields is required on
+// creation, only the name, description, admin state and QoS policy can be
+// updated.
+type LoadBalancerOpts struct {
+	Name             *string  `json:"name,omitempty"`
+	Description      *string  `json:"description,omitempty"`
+	AdminStateUp     *bool    `json:"admin_state_up,omitempty"`
+	VipAddress       string   `json:"vip_address,omitempty"`
+	VipPortId        string   `json:"vip_port_id,omitempty"`
+	VipSubnetId      string   `json:"vip_subnet_id,omitempty"`
+	VipNetworkId     string   `json:"vip_network_id,omitempty"`
+	VipQosPolicyId   *string  `json:"vip_qos_policy_id,omitempty"`
+	Provider         string   `json:"provider,omitempty"`
+	FlavorId         string   `json:"flavor_id,omitempty"`
+	AvailabilityZone string   `json:"availability_zone,omitempty"`
+	ProjectId        string   `json:"project_id,omitempty"`
+	Tags             []string `json:"tags,omitempty"`
+}
+
+// LoadBalancerStatus is the status tree of a load balancer and all its
+// children.
+type LoadBalancerStatus struct {
+	Id                 string           `json:"id"`
+	Name               string           `json:"name"`
+	ProvisioningStatus string           `json:"provisioning_status"`
+	OperatingStatus    string           `json:"operating_status"`
+	Listeners          []ListenerStatus `json:"listeners"`
+}
+
+type ListenerStatus struct {
+	Id                 string         `json:"id"`
+	Name               string         `json:"name"`
+	ProvisioningStatus string         `json:"provisioning_status"`
+	OperatingStatus    string         `json:"operating_status"`
+	Pools              []PoolStatus   `json:"pools"`
+	L7Policies         []StatusEntity `json:"l7policies"`
+}
+
+type PoolStatus struct {
+	Id                 string         `json:"id"`
+	Name               string         `json:"name"`
+	ProvisioningStatus string         `json:"provisioning_status"`
+	OperatingStatus    string         `json:"operating_status"`
+	HealthMonitor      *StatusEntity  `json:"health_monitor"`
+	Members            []MemberStatus `json:"members"`
+}
+
+type MemberStatus struct {
+	Id                 string `json:"id"`
+	Name               string `json:"name"`
+	Address            string `json:"address"`
+	ProtocolPort       int    `json:"protocol_port"`
+	ProvisioningStatus string `json:"provisioning_status"`
+	OperatingStatus    string `json:"operating_status"`
+}
+
+type StatusEntity struct {
+	Id                 string `json:"id"`
+	Name               string `json:"name"`
+	Type               string `json:"type"`
+	ProvisioningStatus string `json:"provisioning_status"`
+	OperatingStatus    string `json:"operating_status"`
+}
+
+func loadBalancerEndpoint(auth identity.Auth) string {
+
+	return openstack.UnversionedEndpoint(auth.EndpointList["load-balancer"])
+}
+
+func GetLoadBalancers(auth identity.Auth) (loadBalancers []LoadBalancer, err error) {
+
+	req := gorequest.New()
+
+	url := fmt.Sprintf("%s/v2/lbaas/loadbalancers",
+		loadBalancerEndpoint(auth))
+
+	resp, body, errs := req.Get(url).
+		Set("Content-Type", "application/json").
+		Set("Accept", "application/json").
+		Set("X-Auth-Token", auth.Access.Token.Id).
+		End()
+
+	if errs != nil {
+		err = errs[len(errs)-1]
+		return
+	}
+
+	if err = openstack.CheckHttpResponseStatusCode(resp.StatusCode); err != nil {
+		return
+	}
+
+	var r = loadBalancersResp{}
+	if err = json.Unmarshal([]byte(body), &r); err != nil {
+		return
+	}
+
+	loadBalancers = r.LoadBalancers
+	err = nil
+	return
+}
+
+func GetLoadBalancer(auth identity.Auth, id string) (loadBalancer LoadBalancer, err error) {
+
+	req := gorequest.New()
+
+	url := fmt.Sprintf("%s/v2/lbaas/loadbalancers/%s",
+		loadBalancerEndpoint(auth),
+		id)
+
+	resp, body, errs := req.Get(url).
+		Set("Content-Type", "application/json").
+		Set("Accept", "application/json").
+		Set("X-Auth-Token", auth.Access.Token.Id).
+		End()
+
+	if errs != nil {
+		err = errs[len(errs)-1]
+		return
+	}
+
+	if err = openstack.CheckHttpResponseStatusCode(resp.StatusCode); err != nil {
+		return
+	}
+
+	var r = loadBalancerResp{}
+	if err = json.Unmarshal([]byte(body), &r); err != nil {
+		return
+	}
+
+	loadBalancer = r.LoadBalancer
+	err = nil
+	return
+}
+
+// CreateLoadBalancer returns while the load balancer is PENDING_CREATE.
+func CreateLoadBalancer(auth identity.Auth, opts LoadBalancerOpts) (loadBalancer LoadBalancer, err error) {
+
+	req := gorequest.New()
+
+	url := fmt.Sprintf("%s/v2/lbaas/loadbalancers",
+		loadBalancerEndpoint(auth))
+
+	b, err := json.Marshal(loadBalancerReq{opts})
+	if err != nil {
+		return
+	}
+
+	resp, body, errs := req.Post(url).
+		Set("Content-Type", "application/json").
+		Set("Accept", "application/json").
+		Set("X-Auth-Token", auth.Access.Token.Id).
+		Send(string(b)).
+		End()
+
+	if errs != nil {
+		err = errs[len(errs)-1]
+		return
+	}
+
+	if err = openstack.CheckHttpResponseStatusCode(resp.StatusCode); err != nil {
+		return
+	}
+
+	var r = loadBalancerResp{}
+	if err = json.Unmarshal([]byte(body), &r); err != nil {
+		return
+	}
+
+	loadBalancer = r.LoadBalancer
+	err = nil
+	return
+}
+
+func UpdateLoadBalancer(auth identity.Auth, id string, opts LoadBalancerOpts) (loadBalancer LoadBalancer, err error) {
+
+	req := gorequest.New()
+
+	url := fmt.Sprintf("%s/v2/lbaas/loadbalancers/%s",
+		loadBalancerEndpoint(auth),
+		id)
+
+	b, err := json.Marshal(loadBalancerReq{opts})
+	if err != nil {
+		return
+	}
+
+	resp, body, errs := req.Put(url).
+		Set("Content-Type", "application/json").
+		Set("Accept", "application/json").
+		Set("X-Auth-Token", auth.Access.Token.Id).
+		Send(string(b)).
+		End()
+
+	if errs != nil {
+		err = errs[len(errs)-1]
+		return
+	}
+
+	if err = openstack.CheckHttpResponseStatusCode(resp.StatusCode); err != nil {
+		return
+	}
+
+	var r = loadBalancerResp{}
+	if err = json.Unmarshal([]byte(body), &r); err != nil {
+		return
+	}
+
+	loadBalancer = r.LoadBalancer
+	err = nil
+	return
+}
+
+// DeleteLoadBalancer deletes the load balancer, cascade also deletes its
+// listeners, pools, members and health monitors.
+func DeleteLoadBalancer(auth identity.Auth, id string, cascade bool) (err error) {
+
+	req := gorequest.New()
+
+	url := fmt.Sprintf("%s/v2/lbaas/loadbalancers/%s",
+		loadBalancerEndpoint(auth),
+		id)
+	if cascade {
+		url += "?cascade=true"
+	}
+
+	resp, _, errs := req.Delete(url).
+		Set("Content-Type", "application/json").
+		Set("Accept", "application/json").
+		Set("X-Auth-Token", auth.Access.Token.Id).
+		End()
+
+	if errs != nil {
+		err = errs[len(errs)-1]
+		return
+	}
+
+	if err = openstack.CheckHttpResponseStatusCode(resp.StatusCode); err != nil {
+		return
+	}
+
+	err = nil
+	return
+}
+
+func GetLoadBalancerStatusTree(auth identity.Auth, id string) (status LoadBalancerStatus, err error) {
+
+	req := gorequest.New()
+
+	url := fmt.Sprintf("%s/v2/lbaas/loadbalancers/%s/status",
+		loadBalancerEndpoint(auth),
+		id)
+
+	resp, body, errs := req.Get(url).
+		Set("Content-Type", "application/json").
+		Set("Accept", "application/json").
+		Set("X-Auth-Token", auth.Access.Token.Id).
+		End()
+
+	if errs != nil {
+		err = errs[len(errs)-1]
+		return
+	}
+
+	if err = openstack.CheckHttpResponseStatusCode(resp.StatusCode); err != nil {
+		return
+	}
+
+	var r = statusTreeResp{}
+	if err = json.Unmarshal([]byte(body), &r); err != nil {
+		return
+	}
+
+	status = r.Statuses.LoadBalancer
+	err = nil
+	return
+}
+
+// FailoverLoadBalancer rebuilds the amphorae of the load balancer, it
+// requires admin rights.
+func FailoverLoadBalancer(auth identity.Auth, id string) (err error) {
+
+	req := gorequest.New()
+
+	url := fmt.Sprintf("%s/v2/lbaas/loadbalancers/%s/failover",
+		loadBalancerEndpoint(auth),
+		id)
+
+	resp, _, errs := req.Put(url).
+		Set("Content-Type", "application/json").
+		Set("Accept", "application/json").
+		Set("X-Auth-Token", auth.Access.Token.Id).
+		End()
+
+	if errs != nil {
+		err = errs[len(errs)-1]
+		return
+	}
+
+	if err = openstack.CheckHttpResponseStatusCode(resp.StatusCode); err != nil {
+		return
+	}
+
+	err = nil
+	return
+}
@@ -0,0 +1,276 @@
+package loadbalancer
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/gertd/go-openstack"
+	"github.com/gertd/go-openstack/identity"
+
+	"github.com/parnurzeal/gorequest"
+)
+
+type membersResp struct {
+	Members []Member `json:"members"`
+}
+
+type memberResp struct {
+	Member Member `json:"member"`
+}
+
+type memberReq struct {
+	Member MemberOpts `json:"member"`
+}
+
+type membersReq struct {
+	Members []MemberOpts `json:"members"`
+}
+
+type Member struct {
+	Id                 string   `json:"id"`
+	Name               string   `json:"name"`
+	Address            string   `json:"address"`
+	ProtocolPort       int      `json:"protocol_port"`
+	Weight             int      `json:"weight"`
+	SubnetId           string   `json:"subnet_id"`
+	Backup             bool     `json:"backup"`
+	MonitorAddress     string   `json:"monitor_address"`
+	MonitorPort        int      `json:"monitor_port"`
+	AdminStateUp       bool     `json:"admin_state_up"`
+	ProvisioningStatus string   `json:"provisioning_status"`
+	OperatingStatus    string   `json:"operating_status"`
+	ProjectId          string   `json:"project_id"`
+	Tags               []string `json:"tags"`
+	CreatedAt          string   `json:"created_at"`
+	UpdatedAt          string   `json:"updated_at"`
+}
+
+// MemberOpts is used to create and update members, nil and empty fields
+// are not sent. Address, ProtocolPort and SubnetId can only be set on
+// creation.
+type MemberOpts struct {
+	Name           *string  `json:"name,omitempty"`
+	Address        string   `json:"address,omitempty"`
+	ProtocolPort   int      `json:"protocol_port,omitempty"`
+	Weight         *int     `json:"weight,omitempty"`
+	SubnetId       string   `json:"subnet_id,omitempty"`
+	Backup         *bool    `json:"backup,omitempty"`
+	MonitorAddress *string  `json:"monitor_address,omitempty"`
+	MonitorPort    *int     `json:"monitor_port,omitempty"`
+	AdminStateUp   *bool    `json:"admin_state_up,omitempty"`
+	Tags           []string `json:"tags,omitempty"`
+}
+
+func GetMembers(auth identity.Auth, poolId string) (members []Member, err error) {
+
+	req := gorequest.New()
+
+	url := fmt.Sprintf("%s/v2/lbaas/pools/%s/members",
+		loadBalancerEndpoint(auth),
+		poolId)
+
+	resp, body, errs := req.Get(url).
+		Set("Content-Type", "application/json").
+		Set("Accept", "application/json").
+		Set("X-Auth-Token", auth.Access.Token.Id).
+		End()
+
+	if errs != nil {
+		err = errs[len(errs)-1]
+		return
+	}
+
+	if err = openstack.CheckHttpResponseStatusCode(resp.StatusCode); err != nil {
+		return
+	}
+
+	var r = membersResp{}
+	if err = json.Unmarshal([]byte(body), &r); err != nil {
+		return
+	}
+
+	members = r.Members
+	err = nil
+	return
+}
+
+func GetMember(auth identity.Auth, poolId string, id string) (member Member, err error) {
+
+	req := gorequest.New()
+
+	url := fmt.Sprintf("%s/v2/lbaas/pools/%s/members/%s",
+		loadBalancerEndpoint(auth),
+		poolId,
+		id)
+
+	resp, body, errs := req.Get(url).
+		Set("Content-Type", "application/json").
+		Set("Accept", "application/json").
+		Set("X-Auth-Token", auth.Access.Token.Id).
+		End()
+
+	if errs != nil {
+		err = errs[len(errs)-1]
+		return
+	}
+
+	if err = openstack.CheckHttpResponseStatusCode(resp.StatusCode); err != nil {
+		return
+	}
+
+	var r = memberResp{}
+	if err = json.Unmarshal([]byte(body), &r); err != nil {
+		return
+	}
+
+	member = r.Member
+	err = nil
+	return
+}
+
+func CreateMember(auth identity.Auth, poolId string, opts MemberOpts) (member Member, err error) {
+
+	req := gorequest.New()
+
+	url := fmt.Sprintf("%s/v2/lbaas/pools/%s/members",
+		loadBalancerEndpoint(auth),
+		poolId)
+
+	b, err := json.Marshal(memberReq{opts})
+	if err != nil {
+		return
+	}
+
+	resp, body, errs := req.Post(url).
+		Set("Content-Type", "application/json").
+		Set("Accept", "application/json").
+		Set("X-Auth-Token", auth.Access.Token.Id).
+		Send(string(b)).
+		End()
+
+	if errs != nil {
+		err = errs[len(errs)-1]
+		return
+	}
+
+	if err = openstack.CheckHttpResponseStatusCode(resp.StatusCode); err != nil {
+		return
+	}
+
+	var r = memberResp{}
+	if err = json.Unmarshal([]byte(body), &r); err != nil {
+		return
+	}
+
+	member = r.Member
+	err = nil
+	return
+}
+
+func UpdateMember(auth identity.Auth, poolId string, id string, opts MemberOpts) (member Member, err error) {
+
+	req := gorequest.New()
+
+	url := fmt.Sprintf("%s/v2/lbaas/pools/%s/members/%s",
+		loadBalancerEndpoint(auth),
+		poolId,
+		id)
+
+	b, err := json.Marshal(memberReq{opts})
+	if err != nil {
+		return
+	}
+
+	resp, body, errs := req.Put(url).
+		Set("Content-Type", "application/json").
+		Set("Accept", "application/json").
+		Set("X-Auth-Token", auth.Access.Token.Id).
+		Send(string(b)).
+		End()
+
+	if errs != nil {
+		err = errs[len(errs)-1]
+		return
+	}
+
+	if err = openstack.CheckHttpResponseStatusCode(resp.StatusCode); err != nil {
+		return
+	}
+
+	var r = memberResp{}
+	if err = json.Unmarshal([]byte(body), &r); err != nil {
+		return
+	}
+
+	member = r.Member
+	err = nil
+	return
+}
+
+func DeleteMember(auth identity.Auth, poolId string, id string) (err error) {
+
+	req := gorequest.New()
+
+	url := fmt.Sprintf("%s/v2/lbaas/pools/%s/members/%s",
+		loadBalancerEndpoint(auth),
+		poolId,
+		id)
+
+	resp, _, errs := req.Delete(url).
+		Set("Content-Type", "application/json").
+		Set("Accept", "application/json").
+		Set("X-Auth-Token", auth.Access.Token.Id).
+		End()
+
+	if errs != nil {
+		err = errs[len(errs)-1]
+		return
+	}
+
+	if err = openstack.CheckHttpResponseStatusCode(resp.StatusCode); err != nil {
+		return
+	}
+
+	err = nil
+	return
+}
+
+// BatchUpdateMembers replaces the members of the pool with members in a
+// single change: members are matched on address and port, missing ones are
+// created, changed ones updated and the others deleted.
+func BatchUpdateMembers(auth identity.Auth, poolId string, members []MemberOpts) (err error) {
+
+	req := gorequest.New()
+
+	url := fmt.Sprintf("%s/v2/lbaas/pools/%s/members",
+		loadBalancerEndpoint(auth),
+		poolId)
+
+	if members == nil {
+		members = []MemberOpts{}
+	}
+
+	b, err := json.Marshal(membersReq{members})
+	if err != nil {
+		return
+	}
+
+	resp, _, errs := req.Put(url).
+		Set("Content-Type", "application/json").
+		Set("Accept", "application/json").
+		Set("X-Auth-Token", auth.Access.Token.Id).
+		Send(string(b)).
+		End()
+
+	if errs != nil {
+		err = errs[len(errs)-1]
+		return
+	}
+
+	if err = openstack.CheckHttpResponseStatusCode(resp.StatusCode); err != nil {
+		return
+	}
+
+	err = nil
+	return
+}
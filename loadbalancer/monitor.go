@@ -0,0 +1,236 @@
+package loadbalancer
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/gertd/go-openstack"
+	"github.com/gertd/go-openstack/identity"
+
+	"github.com/parnurzeal/gorequest"
+)
+
+type healthMonitorsResp struct {
+	HealthMonitors []HealthMonitor `json:"healthmonitors"`
+}
+
+type healthMonitorResp struct {
+	HealthMonitor HealthMonitor `json:"healthmonitor"`
+}
+
+type healthMonitorReq struct {
+	HealthMonitor HealthMonitorOpts `json:"healthmonitor"`
+}
+
+// Delay and Timeout are in seconds. HTTPMethod, URLPath and ExpectedCodes
+// only apply to the HTTP and HTTPS types.
+type HealthMonitor struct {
+	Id                 string   `json:"id"`
+	Name               string   `json:"name"`
+	Type               string   `json:"type"`
+	Delay              int      `json:"delay"`
+	Timeout            int      `json:"timeout"`
+	MaxRetries         int      `json:"max_retries"`
+	MaxRetriesDown     int      `json:"max_retries_down"`
+	HTTPMethod         string   `json:"http_method"`
+	HTTPVersion        float64  `json:"http_version"`
+	URLPath            string   `json:"url_path"`
+	ExpectedCodes      string   `json:"expected_codes"`
+	DomainName         string   `json:"domain_name"`
+	AdminStateUp       bool     `json:"admin_state_up"`
+	Pools              []IdRef  `json:"pools"`
+	ProvisioningStatus string   `json:"provisioning_status"`
+	OperatingStatus    string   `json:"operating_status"`
+	ProjectId          string   `json:"project_id"`
+	Tags               []string `json:"tags"`
+	CreatedAt          string   `json:"created_at"`
+	UpdatedAt          string   `json:"updated_at"`
+}
+
+// HealthMonitorOpts is used to create and update health monitors, nil and
+// empty fields are not sent. PoolId and Type can only be set on creation.
+type HealthMonitorOpts struct {
+	PoolId         string   `json:"pool_id,omitempty"`
+	Name           *string  `json:"name,omitempty"`
+	Type           string   `json:"type,omitempty"`
+	Delay          int      `json:"delay,omitempty"`
+	Timeout        int      `json:"timeout,omitempty"`
+	MaxRetries     int      `json:"max_retries,omitempty"`
+	MaxRetriesDown int      `json:"max_retries_down,omitempty"`
+	HTTPMethod     string   `json:"http_method,omitempty"`
+	HTTPVersion    float64  `json:"http_version,omitempty"`
+	URLPath        string   `json:"url_path,omitempty"`
+	ExpectedCodes  string   `json:"expected_codes,omitempty"`
+	DomainName     string   `json:"domain_name,omitempty"`
+	AdminStateUp   *bool    `json:"admin_state_up,omitempty"`
+	Tags           []string `json:"tags,omitempty"`
+}
+
+func GetHealthMonitors(auth identity.Auth) (healthMonitors []HealthMonitor, err error) {
+
+	req := gorequest.New()
+
+	url := fmt.Sprintf("%s/v2/lbaas/healthmonitors",
+		loadBalancerEndpoint(auth))
+
+	resp, body, errs := req.Get(url).
+		Set("Content-Type", "application/json").
+		Set("Accept", "application/json").
+		Set("X-Auth-Token", auth.Access.Token.Id).
+		End()
+
+	if errs != nil {
+		err = errs[len(errs)-1]
+		return
+	}
+
+	if err = openstack.CheckHttpResponseStatusCode(resp.StatusCode); err != nil {
+		return
+	}
+
+	var r = healthMonitorsResp{}
+	if err = json.Unmarshal([]byte(body), &r); err != nil {
+		return
+	}
+
+	healthMonitors = r.HealthMonitors
+	err = nil
+	return
+}
+
+func GetHealthMonitor(auth identity.Auth, id string) (healthMonitor HealthMonitor, err error) {
+
+	req := gorequest.New()
+
+	url := fmt.Sprintf("%s/v2/lbaas/healthmonitors/%s",
+		loadBalancerEndpoint(auth),
+		id)
+
+	resp, body, errs := req.Get(url).
+		Set("Content-Type", "application/json").
+		Set("Accept", "application/json").
+		Set("X-Auth-Token", auth.Access.Token.Id).
+		End()
+
+	if errs != nil {
+		err = errs[len(errs)-1]
+		return
+	}
+
+	if err = openstack.CheckHttpResponseStatusCode(resp.StatusCode); err != nil {
+		return
+	}
+
+	var r = healthMonitorResp{}
+	if err = json.Unmarshal([]byte(body), &r); err != nil {
+		return
+	}
+
+	healthMonitor = r.HealthMonitor
+	err = nil
+	return
+}
+
+func CreateHealthMonitor(auth identity.Auth, opts HealthMonitorOpts) (healthMonitor HealthMonitor, err error) {
+
+	req := gorequest.New()
+
+	url := fmt.Sprintf("%s/v2/lbaas/healthmonitors",
+		loadBalancerEndpoint(auth))
+
+	b, err := json.Marshal(healthMonitorReq{opts})
+	if err != nil {
+		return
+	}
+
+	resp, body, errs := req.Post(url).
+		Set("Content-Type", "application/json").
+		Set("Accept", "application/json").
+		Set("X-Auth-Token", auth.Access.Token.Id).
+		Send(string(b)).
+		End()
+
+	if errs != nil {
+		err = errs[len(errs)-1]
+		return
+	}
+
+	if err = openstack.CheckHttpResponseStatusCode(resp.StatusCode); err != nil {
+		return
+	}
+
+	var r = healthMonitorResp{}
+	if err = json.Unmarshal([]byte(body), &r); err != nil {
+		return
+	}
+
+	healthMonitor = r.HealthMonitor
+	err = nil
+	return
+}
+
+func UpdateHealthMonitor(auth identity.Auth, id string, opts HealthMonitorOpts) (healthMonitor HealthMonitor, err error) {
+
+	req := gorequest.New()
+
+	url := fmt.Sprintf("%s/v2/lbaas/healthmonitors/%s",
+		loadBalancerEndpoint(auth),
+		id)
+
+	b, err := json.Marshal(healthMonitorReq{opts})
+	if err != nil {
+		return
+	}
+
+	resp, body, errs := req.Put(url).
+		Set("Content-Type", "application/json").
+		Set("Accept", "application/json").
+		Set("X-Auth-Token", auth.Access.Token.Id).
+		Send(string(b)).
+		End()
+
+	if errs != nil {
+		err = errs[len(errs)-1]
+		return
+	}
+
+	if err = openstack.CheckHttpResponseStatusCode(resp.StatusCode); err != nil {
+		return
+	}
+
+	var r = healthMonitorResp{}
+	if err = json.Unmarshal([]byte(body), &r); err != nil {
+		return
+	}
+
+	healthMonitor = r.HealthMonitor
+	err = nil
+	return
+}
+
+func DeleteHealthMonitor(auth identity.Auth, id string) (err error) {
+
+	req := gorequest.New()
+
+	url := fmt.Sprintf("%s/v2/lbaas/healthmonitors/%s",
+		loadBalancerEndpoint(auth),
+		id)
+
+	resp, _, errs := req.Delete(url).
+		Set("Content-Type", "application/json").
+		Set("Accept", "application/json").
+		Set("X-Auth-Token", auth.Access.Token.Id).
+		End()
+
+	if errs != nil {
+		err = errs[len(errs)-1]
+		return
+	}
+
+	if err = openstack.CheckHttpResponseStatusCode(resp.StatusCode); err != nil {
+		return
+	}
+
+	err = nil
+	return
+}
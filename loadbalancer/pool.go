@@ -0,0 +1,238 @@
+package loadbalancer
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/gertd/go-openstack"
+	"github.com/gertd/go-openstack/identity"
+
+	"github.com/parnurzeal/gorequest"
+)
+
+type poolsResp struct {
+	Pools []Pool `json:"pools"`
+}
+
+type poolResp struct {
+	Pool Pool `json:"pool"`
+}
+
+type poolReq struct {
+	Pool PoolOpts `json:"pool"`
+}
+
+type Pool struct {
+	Id                 string              `json:"id"`
+	Name               string              `json:"name"`
+	Description        string              `json:"description"`
+	Protocol           string              `json:"protocol"`
+	LBAlgorithm        string              `json:"lb_algorithm"`
+	SessionPersistence *SessionPersistence `json:"session_persistence"`
+	TLSEnabled         bool                `json:"tls_enabled"`
+	TLSContainerRef    string              `json:"tls_container_ref"`
+	CATLSContainerRef  string              `json:"ca_tls_container_ref"`
+	AdminStateUp       bool                `json:"admin_state_up"`
+	HealthMonitorId    string              `json:"healthmonitor_id"`
+	Listeners          []IdRef             `json:"listeners"`
+	LoadBalancers      []IdRef             `json:"loadbalancers"`
+	Members            []IdRef             `json:"members"`
+	ProvisioningStatus string              `json:"provisioning_status"`
+	OperatingStatus    string              `json:"operating_status"`
+	ProjectId          string              `json:"project_id"`
+	Tags               []string            `json:"tags"`
+	CreatedAt          string              `json:"created_at"`
+	UpdatedAt          string              `json:"updated_at"`
+}
+
+// CookieName is only used with the APP_COOKIE type.
+type SessionPersistence struct {
+	Type       string `json:"type"`
+	CookieName string `json:"cookie_name,omitempty"`
+}
+
+// PoolOpts is used to create and update pools, nil and empty fields are
+// not sent. One of LoadBalancerId and ListenerId is required on creation.
+type PoolOpts struct {
+	LoadBalancerId     string              `json:"loadbalancer_id,omitempty"`
+	ListenerId         string              `json:"listener_id,omitempty"`
+	Name               *string             `json:"name,omitempty"`
+	Description        *string             `json:"description,omitempty"`
+	Protocol           string              `json:"protocol,omitempty"`
+	LBAlgorithm        string              `json:"lb_algorithm,omitempty"`
+	SessionPersistence *SessionPersistence `json:"session_persistence,omitempty"`
+	TLSEnabled         *bool               `json:"tls_enabled,omitempty"`
+	TLSContainerRef    *string             `json:"tls_container_ref,omitempty"`
+	CATLSContainerRef  *string             `json:"ca_tls_container_ref,omitempty"`
+	AdminStateUp       *bool               `json:"admin_state_up,omitempty"`
+	Tags               []string            `json:"tags,omitempty"`
+}
+
+func GetPools(auth identity.Auth) (pools []Pool, err error) {
+
+	req := gorequest.New()
+
+	url := fmt.Sprintf("%s/v2/lbaas/pools",
+		loadBalancerEndpoint(auth))
+
+	resp, body, errs := req.Get(url).
+		Set("Content-Type", "application/json").
+		Set("Accept", "application/json").
+		Set("X-Auth-Token", auth.Access.Token.Id).
+		End()
+
+	if errs != nil {
+		err = errs[len(errs)-1]
+		return
+	}
+
+	if err = openstack.CheckHttpResponseStatusCode(resp.StatusCode); err != nil {
+		return
+	}
+
+	var r = poolsResp{}
+	if err = json.Unmarshal([]byte(body), &r); err != nil {
+		return
+	}
+
+	pools = r.Pools
+	err = nil
+	return
+}
+
+func GetPool(auth identity.Auth, id string) (pool Pool, err error) {
+
+	req := gorequest.New()
+
+	url := fmt.Sprintf("%s/v2/lbaas/pools/%s",
+		loadBalancerEndpoint(auth),
+		id)
+
+	resp, body, errs := req.Get(url).
+		Set("Content-Type", "application/json").
+		Set("Accept", "application/json").
+		Set("X-Auth-Token", auth.Access.Token.Id).
+		End()
+
+	if errs != nil {
+		err = errs[len(errs)-1]
+		return
+	}
+
+	if err = openstack.CheckHttpResponseStatusCode(resp.StatusCode); err != nil {
+		return
+	}
+
+	var r = poolResp{}
+	if err = json.Unmarshal([]byte(body), &r); err != nil {
+		return
+	}
+
+	pool = r.Pool
+	err = nil
+	return
+}
+
+func CreatePool(auth identity.Auth, opts PoolOpts) (pool Pool, err error) {
+
+	req := gorequest.New()
+
+	url := fmt.Sprintf("%s/v2/lbaas/pools",
+		loadBalancerEndpoint(auth))
+
+	b, err := json.Marshal(poolReq{opts})
+	if err != nil {
+		return
+	}
+
+	resp, body, errs := req.Post(url).
+		Set("Content-Type", "application/json").
+		Set("Accept", "application/json").
+		Set("X-Auth-Token", auth.Access.Token.Id).
+		Send(string(b)).
+		End()
+
+	if errs != nil {
+		err = errs[len(errs)-1]
+		return
+	}
+
+	if err = openstack.CheckHttpResponseStatusCode(resp.StatusCode); err != nil {
+		return
+	}
+
+	var r = poolResp{}
+	if err = json.Unmarshal([]byte(body), &r); err != nil {
+		return
+	}
+
+	pool = r.Pool
+	err = nil
+	return
+}
+
+func UpdatePool(auth identity.Auth, id string, opts PoolOpts) (pool Pool, err error) {
+
+	req := gorequest.New()
+
+	url := fmt.Sprintf("%s/v2/lbaas/pools/%s",
+		loadBalancerEndpoint(auth),
+		id)
+
+	b, err := json.Marshal(poolReq{opts})
+	if err != nil {
+		return
+	}
+
+	resp, body, errs := req.Put(url).
+		Set("Content-Type", "application/json").
+		Set("Accept", "application/json").
+		Set("X-Auth-Token", auth.Access.Token.Id).
+		Send(string(b)).
+		End()
+
+	if errs != nil {
+		err = errs[len(errs)-1]
+		return
+	}
+
+	if err = openstack.CheckHttpResponseStatusCode(resp.StatusCode); err != nil {
+		return
+	}
+
+	var r = poolResp{}
+	if err = json.Unmarshal([]byte(body), &r); err != nil {
+		return
+	}
+
+	pool = r.Pool
+	err = nil
+	return
+}
+
+func DeletePool(auth identity.Auth, id string) (err error) {
+
+	req := gorequest.New()
+
+	url := fmt.Sprintf("%s/v2/lbaas/pools/%s",
+		loadBalancerEndpoint(auth),
+		id)
+
+	resp, _, errs := req.Delete(url).
+		Set("Content-Type", "application/json").
+		Set("Accept", "application/json").
+		Set("X-Auth-Token", auth.Access.Token.Id).
+		End()
+
+	if errs != nil {
+		err = errs[len(errs)-1]
+		return
+	}
+
+	if err = openstack.CheckHttpResponseStatusCode(resp.StatusCode); err != nil {
+		return
+	}
+
+	err = nil
+	return
+}
@@ -0,0 +1,102 @@
+package loadbalancer
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/gertd/go-openstack/identity"
+)
+
+const pollInterval = 5 * time.Second
+
+// waitForActive polls status until it returns ProvisioningActive. An error
+// is returned when the resource goes to ProvisioningError or the timeout
+// expires, a timeout of zero waits forever.
+func waitForActive(kind string, id string, timeout time.Duration, status func() (string, error)) (err error) {
+
+	start := time.Now()
+
+	for {
+		var s string
+		if s, err = status(); err != nil {
+			return
+		}
+
+		switch s {
+		case ProvisioningActive:
+			err = nil
+			return
+		case ProvisioningError:
+			err = errors.New(fmt.Sprintf("%s %s provisioning failed", kind, id))
+			return
+		}
+
+		if timeout > 0 && time.Since(start) > timeout {
+			err = errors.New(fmt.Sprintf("timeout waiting for %s %s to become active (status %s)", kind, id, s))
+			return
+		}
+
+		time.Sleep(pollInterval)
+	}
+}
+
+func WaitForLoadBalancerActive(auth identity.Auth, id string, timeout time.Duration) (loadBalancer LoadBalancer, err error) {
+
+	err = waitForActive("load balancer", id, timeout, func() (string, error) {
+		var e error
+		loadBalancer, e = GetLoadBalancer(auth, id)
+		return loadBalancer.ProvisioningStatus, e
+	})
+	return
+}
+
+func WaitForListenerActive(auth identity.Auth, id string, timeout time.Duration) (listener Listener, err error) {
+
+	err = waitForActive("listener", id, timeout, func() (string, error) {
+		var e error
+		listener, e = GetListener(auth, id)
+		return listener.ProvisioningStatus, e
+	})
+	return
+}
+
+func WaitForPoolActive(auth identity.Auth, id string, timeout time.Duration) (pool Pool, err error) {
+
+	err = waitForActive("pool", id, timeout, func() (string, error) {
+		var e error
+		pool, e = GetPool(auth, id)
+		return pool.ProvisioningStatus, e
+	})
+	return
+}
+
+func WaitForMemberActive(auth identity.Auth, poolId string, id string, timeout time.Duration) (member Member, err error) {
+
+	err = waitForActive("member", id, timeout, func() (string, error) {
+		var e error
+		member, e = GetMember(auth, poolId, id)
+		return member.ProvisioningStatus, e
+	})
+	return
+}
+
+func WaitForHealthMonitorActive(auth identity.Auth, id string, timeout time.Duration) (healthMonitor HealthMonitor, err error) {
+
+	err = waitForActive("health monitor", id, timeout, func() (string, error) {
+		var e error
+		healthMonitor, e = GetHealthMonitor(auth, id)
+		return healthMonitor.ProvisioningStatus, e
+	})
+	return
+}
+
+func WaitForL7PolicyActive(auth identity.Auth, id string, timeout time.Duration) (l7Policy L7Policy, err error) {
+
+	err = waitForActive("L7 policy", id, timeout, func() (string, error) {
+		var e error
+		l7Policy, e = GetL7Policy(auth, id)
+		return l7Policy.ProvisioningStatus, e
+	})
+	return
+}
@@ -0,0 +1,211 @@
+package network
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/gertd/go-openstack"
+	"github.com/gertd/go-openstack/identity"
+	"github.com/parnurzeal/gorequest"
+)
+
+type addressScopesResp struct {
+	AddressScopes []AddressScope `json:"address_scopes"`
+}
+
+type addressScopeResp struct {
+	AddressScope AddressScope `json:"address_scope"`
+}
+
+type addressScopeReq struct {
+	AddressScope AddressScopeOpts `json:"address_scope"`
+}
+
+// AddressScope groups subnet pools whose addresses are routed without NAT
+// between each other.
+type AddressScope struct {
+	Id        string `json:"id"`
+	Name      string `json:"name"`
+	IPVersion int    `json:"ip_version"`
+	Shared    bool   `json:"shared"`
+	TenantId  string `json:"tenant_id"`
+	ProjectId string `json:"project_id"`
+}
+
+// AddressScopeOpts is used to create and update address scopes, nil and
+// empty fields are not sent. IPVersion can only be set on creation.
+type AddressScopeOpts struct {
+	Name      *string `json:"name,omitempty"`
+	IPVersion int     `json:"ip_version,omitempty"`
+	Shared    *bool   `json:"shared,omitempty"`
+	TenantId  string  `json:"tenant_id,omitempty"`
+}
+
+func GetAddressScopes(auth identity.Auth) (addressScopes []AddressScope, err error) {
+
+	req := gorequest.New()
+
+	url := fmt.Sprintf("%s/v2.0/address-scopes",
+		auth.EndpointList["network"])
+
+	resp, body, errs := req.Get(url).
+		Set("Content-Type", "application/json").
+		Set("Accept", "application/json").
+		Set("X-Auth-Token", auth.Access.Token.Id).
+		End()
+
+	if errs != nil {
+		err = errs[len(errs)-1]
+		return
+	}
+
+	if err = openstack.CheckHttpResponseStatusCode(resp.StatusCode); err != nil {
+		return
+	}
+
+	var r = addressScopesResp{}
+	if err = json.Unmarshal([]byte(body), &r); err != nil {
+		return
+	}
+
+	addressScopes = r.AddressScopes
+	err = nil
+	return
+}
+
+func GetAddressScope(auth identity.Auth, id string) (addressScope AddressScope, err error) {
+
+	req := gorequest.New()
+
+	url := fmt.Sprintf("%s/v2.0/address-scopes/%s",
+		auth.EndpointList["network"],
+		id)
+
+	resp, body, errs := req.Get(url).
+		Set("Content-Type", "application/json").
+		Set("Accept", "application/json").
+		Set("X-Auth-Token", auth.Access.Token.Id).
+		End()
+
+	if errs != nil {
+		err = errs[len(errs)-1]
+		return
+	}
+
+	if err = openstack.CheckHttpResponseStatusCode(resp.StatusCode); err != nil {
+		return
+	}
+
+	var r = addressScopeResp{}
+	if err = json.Unmarshal([]byte(body), &r); err != nil {
+		return
+	}
+
+	addressScope = r.AddressScope
+	err = nil
+	return
+}
+
+func CreateAddressScope(auth identity.Auth, opts AddressScopeOpts) (addressScope AddressScope, err error) {
+
+	req := gorequest.New()
+
+	url := fmt.Sprintf("%s/v2.0/address-scopes",
+		auth.EndpointList["network"])
+
+	b, err := json.Marshal(addressScopeReq{opts})
+	if err != nil {
+		return
+	}
+
+	resp, body, errs := req.Post(url).
+		Set("Content-Type", "application/json").
+		Set("Accept", "application/json").
+		Set("X-Auth-Token", auth.Access.Token.Id).
+		Send(string(b)).
+		End()
+
+	if errs != nil {
+		err = errs[len(errs)-1]
+		return
+	}
+
+	if err = openstack.CheckHttpResponseStatusCode(resp.StatusCode); err != nil {
+		return
+	}
+
+	var r = addressScopeResp{}
+	if err = json.Unmarshal([]byte(body), &r); err != nil {
+		return
+	}
+
+	addressScope = r.AddressScope
+	err = nil
+	return
+}
+
+func UpdateAddressScope(auth identity.Auth, id string, opts AddressScopeOpts) (addressScope AddressScope, err error) {
+
+	req := gorequest.New()
+
+	url := fmt.Sprintf("%s/v2.0/address-scopes/%s",
+		auth.EndpointList["network"],
+		id)
+
+	b, err := json.Marshal(addressScopeReq{opts})
+	if err != nil {
+		return
+	}
+
+	resp, body, errs := req.Put(url).
+		Set("Content-Type", "application/json").
+		Set("Accept", "application/json").
+		Set("X-Auth-Token", auth.Access.Token.Id).
+		Send(string(b)).
+		End()
+
+	if errs != nil {
+		err = errs[len(errs)-1]
+		return
+	}
+
+	if err = openstack.CheckHttpResponseStatusCode(resp.StatusCode); err != nil {
+		return
+	}
+
+	var r = addressScopeResp{}
+	if err = json.Unmarshal([]byte(body), &r); err != nil {
+		return
+	}
+
+	addressScope = r.AddressScope
+	err = nil
+	return
+}
+
+func DeleteAddressScope(auth identity.Auth, id string) (err error) {
+
+	req := gorequest.New()
+
+	url := fmt.Sprintf("%s/v2.0/address-scopes/%s",
+		auth.EndpointList["network"],
+		id)
+
+	resp, _, errs := req.Delete(url).
+		Set("Content-Type", "application/json").
+		Set("Accept", "application/json").
+		Set("X-Auth-Token", auth.Access.Token.Id).
+		End()
+
+	if errs != nil {
+		err = errs[len(errs)-1]
+		return
+	}
+
+	if err = openstack.CheckHttpResponseStatusCode(resp.StatusCode); err != nil {
+		return
+	}
+
+	err = nil
+	return
+}
@@ -0,0 +1,179 @@
+package network
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+	"net"
+	"sort"
+
+	"github.com/gertd/go-openstack/identity"
+)
+
+type cidrRange struct {
+	cidr  string
+	start *big.Int
+	end   *big.Int
+	ones  int
+	bits  int
+}
+
+func parseCIDR(cidr string) (r cidrRange, err error) {
+
+	ip, ipNet, err := net.ParseCIDR(cidr)
+	if err != nil {
+		return
+	}
+
+	ones, bits := ipNet.Mask.Size()
+
+	addr := ipNet.IP.To16()
+	if ip.To4() != nil {
+		addr = ipNet.IP.To4()
+	}
+
+	r.cidr = ipNet.String()
+	r.ones = ones
+	r.bits = bits
+	r.start = new(big.Int).SetBytes(addr)
+	r.end = new(big.Int).Add(r.start, blockSize(bits-ones))
+	r.end.Sub(r.end, big.NewInt(1))
+
+	err = nil
+	return
+}
+
+func blockSize(hostBits int) *big.Int {
+
+	return new(big.Int).Lsh(big.NewInt(1), uint(hostBits))
+}
+
+func (r cidrRange) overlaps(o cidrRange) bool {
+
+	return r.bits == o.bits && r.start.Cmp(o.end) <= 0 && o.start.Cmp(r.end) <= 0
+}
+
+func formatCIDR(start *big.Int, bits int, prefixLen int) string {
+
+	b := start.Bytes()
+	addr := make([]byte, bits/8)
+	copy(addr[len(addr)-len(b):], b)
+
+	return (&net.IPNet{IP: net.IP(addr), Mask: net.CIDRMask(prefixLen, bits)}).String()
+}
+
+// CIDRsOverlap reports whether the two prefixes share addresses, prefixes
+// of different IP versions never overlap.
+func CIDRsOverlap(a string, b string) (overlap bool, err error) {
+
+	ra, err := parseCIDR(a)
+	if err != nil {
+		return
+	}
+
+	rb, err := parseCIDR(b)
+	if err != nil {
+		return
+	}
+
+	return ra.overlaps(rb), nil
+}
+
+// OverlappingCIDRs returns every pair of overlapping prefixes.
+func OverlappingCIDRs(cidrs []string) (overlaps [][2]string, err error) {
+
+	ranges := make([]cidrRange, len(cidrs))
+	for i, v := range cidrs {
+		if ranges[i], err = parseCIDR(v); err != nil {
+			return
+		}
+	}
+
+	for i := range ranges {
+		for j := i + 1; j < len(ranges); j++ {
+			if ranges[i].overlaps(ranges[j]) {
+				overlaps = append(overlaps, [2]string{cidrs[i], cidrs[j]})
+			}
+		}
+	}
+
+	err = nil
+	return
+}
+
+// NextFreePrefix returns the lowest prefix of length prefixLen within
+// parent which does not overlap any of the used prefixes. Used prefixes of
+// the other IP version or outside parent are ignored.
+func NextFreePrefix(parent string, used []string, prefixLen int) (cidr string, err error) {
+
+	p, err := parseCIDR(parent)
+	if err != nil {
+		return
+	}
+
+	if prefixLen < p.ones || prefixLen > p.bits {
+		err = errors.New(fmt.Sprintf("prefix length %d does not fit in %s", prefixLen, parent))
+		return
+	}
+
+	var taken []cidrRange
+	for _, v := range used {
+		r, e := parseCIDR(v)
+		if e != nil {
+			err = e
+			return
+		}
+		if r.overlaps(p) {
+			taken = append(taken, r)
+		}
+	}
+
+	sort.Slice(taken, func(i, j int) bool { return taken[i].start.Cmp(taken[j].start) < 0 })
+
+	size := blockSize(p.bits - prefixLen)
+	candidate := new(big.Int).Set(p.start)
+
+	for _, t := range taken {
+		end := new(big.Int).Add(candidate, size)
+		end.Sub(end, big.NewInt(1))
+
+		if end.Cmp(t.start) < 0 {
+			break
+		}
+		if t.end.Cmp(candidate) < 0 {
+			continue
+		}
+
+		// move to the first aligned block after the used prefix
+		candidate.Add(t.end, big.NewInt(1))
+		if m := new(big.Int).Mod(candidate, size); m.Sign() != 0 {
+			candidate.Add(candidate, new(big.Int).Sub(size, m))
+		}
+	}
+
+	end := new(big.Int).Add(candidate, size)
+	end.Sub(end, big.NewInt(1))
+	if end.Cmp(p.end) > 0 {
+		err = errors.New(fmt.Sprintf("no free /%d prefix left in %s", prefixLen, parent))
+		return
+	}
+
+	return formatCIDR(candidate, p.bits, prefixLen), nil
+}
+
+// PlanSubnet returns the next free prefix of length prefixLen within parent
+// given the subnets that already exist.
+func PlanSubnet(auth identity.Auth, parent string, prefixLen int) (cidr string, err error) {
+
+	subnets, err := GetSubnets(auth)
+	if err != nil {
+		return
+	}
+
+	used := make([]string, 0, len(subnets))
+	for _, v := range subnets {
+		used = append(used, v.CIDR)
+	}
+
+	return NextFreePrefix(parent, used, prefixLen)
+}
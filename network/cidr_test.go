@@ -0,0 +1,193 @@
+package network
+
+import (
+	"testing"
+)
+
+func TestNextFreePrefix(t *testing.T) {
+
+	tests := []struct {
+		name      string
+		parent    string
+		used      []string
+		prefixLen int
+		want      string
+		wantErr   bool
+	}{
+		{
+			name:      "empty parent",
+			parent:    "10.0.0.0/16",
+			prefixLen: 24,
+			want:      "10.0.0.0/24",
+		},
+		{
+			name:      "gap between used prefixes",
+			parent:    "10.0.0.0/16",
+			used:      []string{"10.0.0.0/24", "10.0.2.0/24"},
+			prefixLen: 24,
+			want:      "10.0.1.0/24",
+		},
+		{
+			name:      "gap too small is skipped",
+			parent:    "10.0.0.0/16",
+			used:      []string{"10.0.0.0/25", "10.0.1.0/24"},
+			prefixLen: 24,
+			want:      "10.0.2.0/24",
+		},
+		{
+			name:      "candidate aligned after a smaller prefix",
+			parent:    "10.0.0.0/16",
+			used:      []string{"10.0.0.0/26"},
+			prefixLen: 24,
+			want:      "10.0.1.0/24",
+		},
+		{
+			name:      "unsorted used prefixes",
+			parent:    "10.0.0.0/16",
+			used:      []string{"10.0.1.0/24", "10.0.0.0/24"},
+			prefixLen: 24,
+			want:      "10.0.2.0/24",
+		},
+		{
+			name:      "prefixes outside parent and of the other version are ignored",
+			parent:    "10.0.0.0/16",
+			used:      []string{"10.1.0.0/24", "fd00::/64"},
+			prefixLen: 24,
+			want:      "10.0.0.0/24",
+		},
+		{
+			name:      "exhausted",
+			parent:    "10.0.0.0/23",
+			used:      []string{"10.0.0.0/24", "10.0.1.0/24"},
+			prefixLen: 24,
+			wantErr:   true,
+		},
+		{
+			name:      "parent used as a whole",
+			parent:    "10.0.0.0/24",
+			used:      []string{"10.0.0.0/16"},
+			prefixLen: 26,
+			wantErr:   true,
+		},
+		{
+			name:      "prefix length shorter than parent",
+			parent:    "10.0.0.0/24",
+			prefixLen: 16,
+			wantErr:   true,
+		},
+		{
+			name:      "IPv6 /64 in a /56",
+			parent:    "2001:db8:0:100::/56",
+			used:      []string{"2001:db8:0:100::/64", "2001:db8:0:101::/64"},
+			prefixLen: 64,
+			want:      "2001:db8:0:102::/64",
+		},
+		{
+			name:      "IPv6 /56 exhausted",
+			parent:    "2001:db8:0:100::/56",
+			used:      []string{"2001:db8:0:100::/57", "2001:db8:0:180::/57"},
+			prefixLen: 64,
+			wantErr:   true,
+		},
+		{
+			name:      "invalid used prefix",
+			parent:    "10.0.0.0/16",
+			used:      []string{"10.0.0.0/33"},
+			prefixLen: 24,
+			wantErr:   true,
+		},
+	}
+
+	for _, tt := range tests {
+		cidr, err := NextFreePrefix(tt.parent, tt.used, tt.prefixLen)
+
+		if tt.wantErr {
+			if err == nil {
+				t.Errorf("%s: got %s, want an error", tt.name, cidr)
+			}
+			continue
+		}
+
+		if err != nil {
+			t.Errorf("%s: %v", tt.name, err)
+			continue
+		}
+		if cidr != tt.want {
+			t.Errorf("%s: got %s, want %s", tt.name, cidr, tt.want)
+		}
+	}
+}
+
+func TestOverlappingCIDRs(t *testing.T) {
+
+	tests := []struct {
+		name    string
+		cidrs   []string
+		want    [][2]string
+		wantErr bool
+	}{
+		{
+			name:  "disjoint",
+			cidrs: []string{"10.0.0.0/24", "10.0.1.0/24", "192.168.0.0/16"},
+		},
+		{
+			name:  "nested",
+			cidrs: []string{"10.0.0.0/16", "10.0.1.0/24"},
+			want:  [][2]string{{"10.0.0.0/16", "10.0.1.0/24"}},
+		},
+		{
+			name:  "identical",
+			cidrs: []string{"10.0.0.0/24", "10.0.0.0/24"},
+			want:  [][2]string{{"10.0.0.0/24", "10.0.0.0/24"}},
+		},
+		{
+			name:  "host bits set",
+			cidrs: []string{"10.0.0.5/24", "10.0.0.128/25"},
+			want:  [][2]string{{"10.0.0.5/24", "10.0.0.128/25"}},
+		},
+		{
+			name:  "every pair",
+			cidrs: []string{"10.0.0.0/8", "10.1.0.0/16", "10.1.2.0/24"},
+			want:  [][2]string{{"10.0.0.0/8", "10.1.0.0/16"}, {"10.0.0.0/8", "10.1.2.0/24"}, {"10.1.0.0/16", "10.1.2.0/24"}},
+		},
+		{
+			name:  "IPv6 nested",
+			cidrs: []string{"2001:db8::/56", "2001:db8:0:1::/64", "2001:db8:0:100::/64"},
+			want:  [][2]string{{"2001:db8::/56", "2001:db8:0:1::/64"}},
+		},
+		{
+			name:  "IPv4 and IPv6 never overlap",
+			cidrs: []string{"0.0.0.0/0", "::/0"},
+		},
+		{
+			name:    "invalid",
+			cidrs:   []string{"10.0.0.0/24", "10.0.0.256/24"},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		overlaps, err := OverlappingCIDRs(tt.cidrs)
+
+		if tt.wantErr {
+			if err == nil {
+				t.Errorf("%s: want an error", tt.name)
+			}
+			continue
+		}
+
+		if err != nil {
+			t.Errorf("%s: %v", tt.name, err)
+			continue
+		}
+		if len(overlaps) != len(tt.want) {
+			t.Errorf("%s: got %v, want %v", tt.name, overlaps, tt.want)
+			continue
+		}
+		for i, v := range overlaps {
+			if v != tt.want[i] {
+				t.Errorf("%s: got %v, want %v", tt.name, v, tt.want[i])
+			}
+		}
+	}
+}
@@ -0,0 +1,507 @@
+package network
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/url"
+
+	"github.com/gertd/go-openstack"
+	"github.com/gertd/go-openstack/identity"
+	"github.com/parnurzeal/gorequest"
+)
+
+type floatingIPsResp struct {
+	FloatingIPs []FloatingIP `json:"floatingips"`
+}
+
+type floatingIPResp struct {
+	FloatingIP FloatingIP `json:"floatingip"`
+}
+
+type floatingIPReq struct {
+	FloatingIP FloatingIPOpts `json:"floatingip"`
+}
+
+type portForwardingsResp struct {
+	PortForwardings []PortForwarding `json:"port_forwardings"`
+}
+
+type portForwardingResp struct {
+	PortForwarding PortForwarding `json:"port_forwarding"`
+}
+
+type portForwardingReq struct {
+	PortForwarding PortForwardingOpts `json:"port_forwarding"`
+}
+
+// PortId and FixedIPAddress are empty when the floating IP is not
+// associated.
+type FloatingIP struct {
+	Id                string   `json:"id"`
+	FloatingIPAddress string   `json:"floating_ip_address"`
+	FloatingNetworkId string   `json:"floating_network_id"`
+	RouterId          string   `json:"router_id"`
+	PortId            string   `json:"port_id"`
+	FixedIPAddress    string   `json:"fixed_ip_address"`
+	Status            string   `json:"status"`
+	Description       string   `json:"description"`
+	TenantId          string   `json:"tenant_id"`
+	ProjectId         string   `json:"project_id"`
+	DnsName           string   `json:"dns_name"`
+	DnsDomain         string   `json:"dns_domain"`
+	Tags              []string `json:"tags"`
+	CreatedAt         string   `json:"created_at"`
+	UpdatedAt         string   `json:"updated_at"`
+}
+
+// FloatingIPOpts is used to create and update floating IPs, nil and empty
+// fields are not sent. A PortId pointing to an empty string disassociates
+// the floating IP. Only PortId, FixedIPAddress and Description can be
+// updated.
+type FloatingIPOpts struct {
+	FloatingNetworkId string  `json:"floating_network_id,omitempty"`
+	FloatingIPAddress string  `json:"floating_ip_address,omitempty"`
+	SubnetId          string  `json:"subnet_id,omitempty"`
+	PortId            *string `json:"port_id,omitempty"`
+	FixedIPAddress    string  `json:"fixed_ip_address,omitempty"`
+	Description       *string `json:"description,omitempty"`
+	TenantId          string  `json:"tenant_id,omitempty"`
+	DnsName           string  `json:"dns_name,omitempty"`
+	DnsDomain         string  `json:"dns_domain,omitempty"`
+}
+
+// FloatingIPFilter narrows GetFloatingIPs, empty fields are not sent.
+type FloatingIPFilter struct {
+	Status            string
+	PortId            string
+	FloatingNetworkId string
+	FloatingIPAddress string
+	FixedIPAddress    string
+	RouterId          string
+}
+
+// PortForwarding forwards traffic received on the floating IP to a fixed
+// IP of an internal port. Either the single ports or the port ranges
+// ("1000:1010") are set.
+type PortForwarding struct {
+	Id                string `json:"id"`
+	InternalPortId    string `json:"internal_port_id"`
+	InternalIPAddress string `json:"internal_ip_address"`
+	InternalPort      int    `json:"internal_port"`
+	ExternalPort      int    `json:"external_port"`
+	InternalPortRange string `json:"internal_port_range"`
+	ExternalPortRange string `json:"external_port_range"`
+	Protocol          string `json:"protocol"`
+	Description       string `json:"description"`
+}
+
+// PortForwardingOpts is used to create and update port forwardings, empty
+// fields are not sent.
+type PortForwardingOpts struct {
+	InternalPortId    string  `json:"internal_port_id,omitempty"`
+	InternalIPAddress string  `json:"internal_ip_address,omitempty"`
+	InternalPort      int     `json:"internal_port,omitempty"`
+	ExternalPort      int     `json:"external_port,omitempty"`
+	InternalPortRange string  `json:"internal_port_range,omitempty"`
+	ExternalPortRange string  `json:"external_port_range,omitempty"`
+	Protocol          string  `json:"protocol,omitempty"`
+	Description       *string `json:"description,omitempty"`
+}
+
+func (opts FloatingIPOpts) MarshalJSON() (b []byte, err error) {
+
+	type floatingIPOpts FloatingIPOpts
+
+	b, err = json.Marshal(floatingIPOpts(opts))
+	if err != nil || opts.PortId == nil || len(*opts.PortId) > 0 {
+		return
+	}
+
+	var attrs map[string]interface{}
+	if err = json.Unmarshal(b, &attrs); err != nil {
+		return
+	}
+
+	attrs["port_id"] = nil
+
+	return json.Marshal(attrs)
+}
+
+func GetFloatingIPs(auth identity.Auth, filter FloatingIPFilter) (floatingIPs []FloatingIP, err error) {
+
+	req := gorequest.New()
+
+	query := url.Values{}
+	if len(filter.Status) > 0 {
+		query.Set("status", filter.Status)
+	}
+	if len(filter.PortId) > 0 {
+		query.Set("port_id", filter.PortId)
+	}
+	if len(filter.FloatingNetworkId) > 0 {
+		query.Set("floating_network_id", filter.FloatingNetworkId)
+	}
+	if len(filter.FloatingIPAddress) > 0 {
+		query.Set("floating_ip_address", filter.FloatingIPAddress)
+	}
+	if len(filter.FixedIPAddress) > 0 {
+		query.Set("fixed_ip_address", filter.FixedIPAddress)
+	}
+	if len(filter.RouterId) > 0 {
+		query.Set("router_id", filter.RouterId)
+	}
+
+	reqUrl := fmt.Sprintf("%s/v2.0/floatingips?%s",
+		auth.EndpointList["network"],
+		query.Encode())
+
+	resp, body, errs := req.Get(reqUrl).
+		Set("Content-Type", "application/json").
+		Set("Accept", "application/json").
+		Set("X-Auth-Token", auth.Access.Token.Id).
+		End()
+
+	if errs != nil {
+		err = errs[len(errs)-1]
+		return
+	}
+
+	if err = openstack.CheckHttpResponseStatusCode(resp.StatusCode); err != nil {
+		return
+	}
+
+	var r = floatingIPsResp{}
+	if err = json.Unmarshal([]byte(body), &r); err != nil {
+		return
+	}
+
+	floatingIPs = r.FloatingIPs
+	err = nil
+	return
+}
+
+func GetFloatingIP(auth identity.Auth, id string) (floatingIP FloatingIP, err error) {
+
+	req := gorequest.New()
+
+	url := fmt.Sprintf("%s/v2.0/floatingips/%s",
+		auth.EndpointList["network"],
+		id)
+
+	resp, body, errs := req.Get(url).
+		Set("Content-Type", "application/json").
+		Set("Accept", "application/json").
+		Set("X-Auth-Token", auth.Access.Token.Id).
+		End()
+
+	if errs != nil {
+		err = errs[len(errs)-1]
+		return
+	}
+
+	if err = openstack.CheckHttpResponseStatusCode(resp.StatusCode); err != nil {
+		return
+	}
+
+	var r = floatingIPResp{}
+	if err = json.Unmarshal([]byte(body), &r); err != nil {
+		return
+	}
+
+	floatingIP = r.FloatingIP
+	err = nil
+	return
+}
+
+func CreateFloatingIP(auth identity.Auth, opts FloatingIPOpts) (floatingIP FloatingIP, err error) {
+
+	req := gorequest.New()
+
+	url := fmt.Sprintf("%s/v2.0/floatingips",
+		auth.EndpointList["network"])
+
+	b, err := json.Marshal(floatingIPReq{opts})
+	if err != nil {
+		return
+	}
+
+	resp, body, errs := req.Post(url).
+		Set("Content-Type", "application/json").
+		Set("Accept", "application/json").
+		Set("X-Auth-Token", auth.Access.Token.Id).
+		Send(string(b)).
+		End()
+
+	if errs != nil {
+		err = errs[len(errs)-1]
+		return
+	}
+
+	if err = openstack.CheckHttpResponseStatusCode(resp.StatusCode); err != nil {
+		return
+	}
+
+	var r = floatingIPResp{}
+	if err = json.Unmarshal([]byte(body), &r); err != nil {
+		return
+	}
+
+	floatingIP = r.FloatingIP
+	err = nil
+	return
+}
+
+func UpdateFloatingIP(auth identity.Auth, id string, opts FloatingIPOpts) (floatingIP FloatingIP, err error) {
+
+	req := gorequest.New()
+
+	url := fmt.Sprintf("%s/v2.0/floatingips/%s",
+		auth.EndpointList["network"],
+		id)
+
+	b, err := json.Marshal(floatingIPReq{opts})
+	if err != nil {
+		return
+	}
+
+	resp, body, errs := req.Put(url).
+		Set("Content-Type", "application/json").
+		Set("Accept", "application/json").
+		Set("X-Auth-Token", auth.Access.Token.Id).
+		Send(string(b)).
+		End()
+
+	if errs != nil {
+		err = errs[len(errs)-1]
+		return
+	}
+
+	if err = openstack.CheckHttpResponseStatusCode(resp.StatusCode); err != nil {
+		return
+	}
+
+	var r = floatingIPResp{}
+	if err = json.Unmarshal([]byte(body), &r); err != nil {
+		return
+	}
+
+	floatingIP = r.FloatingIP
+	err = nil
+	return
+}
+
+func DeleteFloatingIP(auth identity.Auth, id string) (err error) {
+
+	req := gorequest.New()
+
+	url := fmt.Sprintf("%s/v2.0/floatingips/%s",
+		auth.EndpointList["network"],
+		id)
+
+	resp, _, errs := req.Delete(url).
+		Set("Content-Type", "application/json").
+		Set("Accept", "application/json").
+		Set("X-Auth-Token", auth.Access.Token.Id).
+		End()
+
+	if errs != nil {
+		err = errs[len(errs)-1]
+		return
+	}
+
+	if err = openstack.CheckHttpResponseStatusCode(resp.StatusCode); err != nil {
+		return
+	}
+
+	err = nil
+	return
+}
+
+// AssociateFloatingIP associates the floating IP with the port. fixedIP
+// selects the fixed IP of the port and may be empty when the port has a
+// single IPv4 address.
+func AssociateFloatingIP(auth identity.Auth, id string, port Port, fixedIP string) (floatingIP FloatingIP, err error) {
+
+	return UpdateFloatingIP(auth, id, FloatingIPOpts{PortId: &port.Id, FixedIPAddress: fixedIP})
+}
+
+func DisassociateFloatingIP(auth identity.Auth, id string) (floatingIP FloatingIP, err error) {
+
+	portId := ""
+
+	return UpdateFloatingIP(auth, id, FloatingIPOpts{PortId: &portId})
+}
+
+func GetPortForwardings(auth identity.Auth, floatingIPId string) (portForwardings []PortForwarding, err error) {
+
+	req := gorequest.New()
+
+	url := fmt.Sprintf("%s/v2.0/floatingips/%s/port_forwardings",
+		auth.EndpointList["network"],
+		floatingIPId)
+
+	resp, body, errs := req.Get(url).
+		Set("Content-Type", "application/json").
+		Set("Accept", "application/json").
+		Set("X-Auth-Token", auth.Access.Token.Id).
+		End()
+
+	if errs != nil {
+		err = errs[len(errs)-1]
+		return
+	}
+
+	if err = openstack.CheckHttpResponseStatusCode(resp.StatusCode); err != nil {
+		return
+	}
+
+	var r = portForwardingsResp{}
+	if err = json.Unmarshal([]byte(body), &r); err != nil {
+		return
+	}
+
+	portForwardings = r.PortForwardings
+	err = nil
+	return
+}
+
+func GetPortForwarding(auth identity.Auth, floatingIPId string, id string) (portForwarding PortForwarding, err error) {
+
+	req := gorequest.New()
+
+	url := fmt.Sprintf("%s/v2.0/floatingips/%s/port_forwardings/%s",
+		auth.EndpointList["network"],
+		floatingIPId,
+		id)
+
+	resp, body, errs := req.Get(url).
+		Set("Content-Type", "application/json").
+		Set("Accept", "application/json").
+		Set("X-Auth-Token", auth.Access.Token.Id).
+		End()
+
+	if errs != nil {
+		err = errs[len(errs)-1]
+		return
+	}
+
+	if err = openstack.CheckHttpResponseStatusCode(resp.StatusCode); err != nil {
+		return
+	}
+
+	var r = portForwardingResp{}
+	if err = json.Unmarshal([]byte(body), &r); err != nil {
+		return
+	}
+
+	portForwarding = r.PortForwarding
+	err = nil
+	return
+}
+
+func CreatePortForwarding(auth identity.Auth, floatingIPId string, opts PortForwardingOpts) (portForwarding PortForwarding, err error) {
+
+	req := gorequest.New()
+
+	url := fmt.Sprintf("%s/v2.0/floatingips/%s/port_forwardings",
+		auth.EndpointList["network"],
+		floatingIPId)
+
+	b, err := json.Marshal(portForwardingReq{opts})
+	if err != nil {
+		return
+	}
+
+	resp, body, errs := req.Post(url).
+		Set("Content-Type", "application/json").
+		Set("Accept", "application/json").
+		Set("X-Auth-Token", auth.Access.Token.Id).
+		Send(string(b)).
+		End()
+
+	if errs != nil {
+		err = errs[len(errs)-1]
+		return
+	}
+
+	if err = openstack.CheckHttpResponseStatusCode(resp.StatusCode); err != nil {
+		return
+	}
+
+	var r = portForwardingResp{}
+	if err = json.Unmarshal([]byte(body), &r); err != nil {
+		return
+	}
+
+	portForwarding = r.PortForwarding
+	err = nil
+	return
+}
+
+func UpdatePortForwarding(auth identity.Auth, floatingIPId string, id string, opts PortForwardingOpts) (portForwarding PortForwarding, err error) {
+
+	req := gorequest.New()
+
+	url := fmt.Sprintf("%s/v2.0/floatingips/%s/port_forwardings/%s",
+		auth.EndpointList["network"],
+		floatingIPId,
+		id)
+
+	b, err := json.Marshal(portForwardingReq{opts})
+	if err != nil {
+		return
+	}
+
+	resp, body, errs := req.Put(url).
+		Set("Content-Type", "application/json").
+		Set("Accept", "application/json").
+		Set("X-Auth-Token", auth.Access.Token.Id).
+		Send(string(b)).
+		End()
+
+	if errs != nil {
+		err = errs[len(errs)-1]
+		return
+	}
+
+	if err = openstack.CheckHttpResponseStatusCode(resp.StatusCode); err != nil {
+		return
+	}
+
+	var r = portForwardingResp{}
+	if err = json.Unmarshal([]byte(body), &r); err != nil {
+		return
+	}
+
+	portForwarding = r.PortForwarding
+	err = nil
+	return
+}
+
+func DeletePortForwarding(auth identity.Auth, floatingIPId string, id string) (err error) {
+
+	req := gorequest.New()
+
+	url := fmt.Sprintf("%s/v2.0/floatingips/%s/port_forwardings/%s",
+		auth.EndpointList["network"],
+		floatingIPId,
+		id)
+
+	resp, _, errs := req.Delete(url).
+		Set("Content-Type", "application/json").
+		Set("Accept", "application/json").
+		Set("X-Auth-Token", auth.Access.Token.Id).
+		End()
+
+	if errs != nil {
+		err = errs[len(errs)-1]
+		return
+	}
+
+	if err = openstack.CheckHttpResponseStatusCode(resp.StatusCode); err != nil {
+		return
+	}
+
+	err = nil
+	return
+}
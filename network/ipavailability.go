@@ -0,0 +1,158 @@
+package network
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+
+	"github.com/gertd/go-openstack"
+	"github.com/gertd/go-openstack/identity"
+	"github.com/parnurzeal/gorequest"
+)
+
+type networkIPAvailabilitiesResp struct {
+	NetworkIPAvailabilities []NetworkIPAvailability `json:"network_ip_availabilities"`
+}
+
+type networkIPAvailabilityResp struct {
+	NetworkIPAvailability NetworkIPAvailability `json:"network_ip_availability"`
+}
+
+// The IP counts are floats as IPv6 subnets overflow 64 bit integers.
+type NetworkIPAvailability struct {
+	NetworkId            string                 `json:"network_id"`
+	NetworkName          string                 `json:"network_name"`
+	TenantId             string                 `json:"tenant_id"`
+	ProjectId            string                 `json:"project_id"`
+	TotalIPs             float64                `json:"total_ips"`
+	UsedIPs              float64                `json:"used_ips"`
+	SubnetIPAvailability []SubnetIPAvailability `json:"subnet_ip_availability"`
+}
+
+type SubnetIPAvailability struct {
+	SubnetId   string  `json:"subnet_id"`
+	SubnetName string  `json:"subnet_name"`
+	CIDR       string  `json:"cidr"`
+	IPVersion  int     `json:"ip_version"`
+	TotalIPs   float64 `json:"total_ips"`
+	UsedIPs    float64 `json:"used_ips"`
+}
+
+// SubnetUtilisation is the share of the allocatable addresses of the
+// subnet in use, between 0 and 1.
+type SubnetUtilisation struct {
+	Subnet      Subnet
+	TotalIPs    float64
+	UsedIPs     float64
+	Utilisation float64
+}
+
+type ByUtilisation []SubnetUtilisation
+
+func (a ByUtilisation) Len() int           { return len(a) }
+func (a ByUtilisation) Swap(i, j int)      { a[i], a[j] = a[j], a[i] }
+func (a ByUtilisation) Less(i, j int) bool { return a[i].Utilisation < a[j].Utilisation }
+
+func GetNetworkIPAvailabilities(auth identity.Auth) (availabilities []NetworkIPAvailability, err error) {
+
+	req := gorequest.New()
+
+	url := fmt.Sprintf("%s/v2.0/network-ip-availabilities",
+		auth.EndpointList["network"])
+
+	resp, body, errs := req.Get(url).
+		Set("Content-Type", "application/json").
+		Set("Accept", "application/json").
+		Set("X-Auth-Token", auth.Access.Token.Id).
+		End()
+
+	if errs != nil {
+		err = errs[len(errs)-1]
+		return
+	}
+
+	if err = openstack.CheckHttpResponseStatusCode(resp.StatusCode); err != nil {
+		return
+	}
+
+	var r = networkIPAvailabilitiesResp{}
+	if err = json.Unmarshal([]byte(body), &r); err != nil {
+		return
+	}
+
+	availabilities = r.NetworkIPAvailabilities
+	err = nil
+	return
+}
+
+func GetNetworkIPAvailability(auth identity.Auth, networkId string) (availability NetworkIPAvailability, err error) {
+
+	req := gorequest.New()
+
+	url := fmt.Sprintf("%s/v2.0/network-ip-availabilities/%s",
+		auth.EndpointList["network"],
+		networkId)
+
+	resp, body, errs := req.Get(url).
+		Set("Content-Type", "application/json").
+		Set("Accept", "application/json").
+		Set("X-Auth-Token", auth.Access.Token.Id).
+		End()
+
+	if errs != nil {
+		err = errs[len(errs)-1]
+		return
+	}
+
+	if err = openstack.CheckHttpResponseStatusCode(resp.StatusCode); err != nil {
+		return
+	}
+
+	var r = networkIPAvailabilityResp{}
+	if err = json.Unmarshal([]byte(body), &r); err != nil {
+		return
+	}
+
+	availability = r.NetworkIPAvailability
+	err = nil
+	return
+}
+
+// GetSubnetsAboveUtilisation returns the subnets whose utilisation is at or
+// above threshold (e.g. 0.8), the most utilised first. The IP availability
+// API is admin only by default.
+func GetSubnetsAboveUtilisation(auth identity.Auth, threshold float64) (subnets []SubnetUtilisation, err error) {
+
+	all, err := GetSubnets(auth)
+	if err != nil {
+		return
+	}
+
+	availabilities, err := GetNetworkIPAvailabilities(auth)
+	if err != nil {
+		return
+	}
+
+	usage := map[string]SubnetIPAvailability{}
+	for _, n := range availabilities {
+		for _, s := range n.SubnetIPAvailability {
+			usage[s.SubnetId] = s
+		}
+	}
+
+	for _, v := range all {
+		s, ok := usage[v.Id]
+		if !ok || s.TotalIPs <= 0 {
+			continue
+		}
+		u := SubnetUtilisation{v, s.TotalIPs, s.UsedIPs, s.UsedIPs / s.TotalIPs}
+		if u.Utilisation >= threshold {
+			subnets = append(subnets, u)
+		}
+	}
+
+	sort.Sort(sort.Reverse(ByUtilisation(subnets)))
+
+	err = nil
+	return
+}
@@ -2,35 +2,122 @@ package network
 
 import (
 	"encoding/json"
+	"fmt"
 
+	"github.com/gertd/go-openstack"
 	"github.com/gertd/go-openstack/identity"
 	"github.com/parnurzeal/gorequest"
 )
 
-type networkResp struct {
+type networksResp struct {
 	Networks []Network `json:"networks"`
 }
 
+type networkResp struct {
+	Network Network `json:"network"`
+}
+
+type networkReq struct {
+	Network NetworkOpts `json:"network"`
+}
+
 type Network struct {
-	Id                  string   `json:"id"`
-	Name                string   `json:"name"`
-	Status              string   `json:"status"`
-	Subnets             []string `json:"subnets"`
-	TenantId            string   `json:"tenant_id"`
-	RouterExternal      bool     `json:"router:external"`
-	AdminStateUp        bool     `json:"admin_state_up"`
-	Shared              bool     `json:"shared"`
-	PortSecurityEnabled bool     `json:"port_security_enabled"`
+	Id                    string    `json:"id"`
+	Name                  string    `json:"name"`
+	Description           string    `json:"description"`
+	Status                string    `json:"status"`
+	Subnets               []string  `json:"subnets"`
+	TenantId              string    `json:"tenant_id"`
+	ProjectId             string    `json:"project_id"`
+	RouterExternal        bool      `json:"router:external"`
+	AdminStateUp          bool      `json:"admin_state_up"`
+	Shared                bool      `json:"shared"`
+	PortSecurityEnabled   bool      `json:"port_security_enabled"`
+	MTU                   int       `json:"mtu"`
+	NetworkType           string    `json:"provider:network_type"`
+	PhysicalNetwork       string    `json:"provider:physical_network"`
+	SegmentationId        int       `json:"provider:segmentation_id"`
+	Segments              []Segment `json:"segments"`
+	AvailabilityZones     []string  `json:"availability_zones"`
+	AvailabilityZoneHints []string  `json:"availability_zone_hints"`
+	QosPolicyId           string    `json:"qos_policy_id"`
+	DnsDomain             string    `json:"dns_domain"`
+	Tags                  []string  `json:"tags"`
+	CreatedAt             string    `json:"created_at"`
+	UpdatedAt             string    `json:"updated_at"`
+}
+
+// Segment is one of the segments of a multi-segment network, the provider
+// attributes of such a network are empty.
+type Segment struct {
+	NetworkType     string `json:"provider:network_type,omitempty"`
+	PhysicalNetwork string `json:"provider:physical_network,omitempty"`
+	SegmentationId  int    `json:"provider:segmentation_id,omitempty"`
+}
+
+// NetworkOpts is used to create and update networks, nil and empty fields
+// are not sent. The provider attributes, Segments and the availability
+// zone hints can usually only be set on creation and require admin rights.
+// A QosPolicyId or DnsDomain pointing to an empty string clears it.
+type NetworkOpts struct {
+	Name                  *string   `json:"name,omitempty"`
+	Description           *string   `json:"description,omitempty"`
+	TenantId              string    `json:"tenant_id,omitempty"`
+	AdminStateUp          *bool     `json:"admin_state_up,omitempty"`
+	Shared                *bool     `json:"shared,omitempty"`
+	RouterExternal        *bool     `json:"router:external,omitempty"`
+	PortSecurityEnabled   *bool     `json:"port_security_enabled,omitempty"`
+	MTU                   *int      `json:"mtu,omitempty"`
+	NetworkType           string    `json:"provider:network_type,omitempty"`
+	PhysicalNetwork       string    `json:"provider:physical_network,omitempty"`
+	SegmentationId        *int      `json:"provider:segmentation_id,omitempty"`
+	Segments              []Segment `json:"segments,omitempty"`
+	AvailabilityZoneHints []string  `json:"availability_zone_hints,omitempty"`
+	QosPolicyId           *string   `json:"qos_policy_id,omitempty"`
+	DnsDomain             *string   `json:"dns_domain,omitempty"`
+}
+
+func (opts NetworkOpts) MarshalJSON() (b []byte, err error) {
+
+	type networkOpts NetworkOpts
+
+	b, err = json.Marshal(networkOpts(opts))
+	if err != nil {
+		return
+	}
+
+	clearQosPolicy := opts.QosPolicyId != nil && len(*opts.QosPolicyId) == 0
+	clearDnsDomain := opts.DnsDomain != nil && len(*opts.DnsDomain) == 0
+	if !clearQosPolicy && !clearDnsDomain {
+		return
+	}
+
+	var attrs map[string]interface{}
+	if err = json.Unmarshal(b, &attrs); err != nil {
+		return
+	}
+
+	if clearQosPolicy {
+		attrs["qos_policy_id"] = nil
+	}
+	if clearDnsDomain {
+		attrs["dns_domain"] = nil
+	}
+
+	return json.Marshal(attrs)
 }
 
-func GetNetworks(url string, token identity.Token) (networks []Network, err error) {
+func GetNetworks(auth identity.Auth) (networks []Network, err error) {
 
 	req := gorequest.New()
 
-	_, body, errs := req.Get(url+"/v2.0/networks").
+	url := fmt.Sprintf("%s/v2.0/networks",
+		auth.EndpointList["network"])
+
+	resp, body, errs := req.Get(url).
 		Set("Content-Type", "application/json").
 		Set("Accept", "application/json").
-		Set("X-Auth-Token", token.Id).
+		Set("X-Auth-Token", auth.Access.Token.Id).
 		End()
 
 	if errs != nil {
@@ -38,7 +125,11 @@ func GetNetworks(url string, token identity.Token) (networks []Network, err erro
 		return
 	}
 
-	var nw = networkResp{}
+	if err = openstack.CheckHttpResponseStatusCode(resp.StatusCode); err != nil {
+		return
+	}
+
+	var nw = networksResp{}
 	if err = json.Unmarshal([]byte(body), &nw); err != nil {
 		return
 	}
@@ -47,3 +138,140 @@ func GetNetworks(url string, token identity.Token) (networks []Network, err erro
 	err = nil
 	return
 }
+
+func GetNetwork(auth identity.Auth, id string) (network Network, err error) {
+
+	req := gorequest.New()
+
+	url := fmt.Sprintf("%s/v2.0/networks/%s",
+		auth.EndpointList["network"],
+		id)
+
+	resp, body, errs := req.Get(url).
+		Set("Content-Type", "application/json").
+		Set("Accept", "application/json").
+		Set("X-Auth-Token", auth.Access.Token.Id).
+		End()
+
+	if errs != nil {
+		err = errs[len(errs)-1]
+		return
+	}
+
+	if err = openstack.CheckHttpResponseStatusCode(resp.StatusCode); err != nil {
+		return
+	}
+
+	var r = networkResp{}
+	if err = json.Unmarshal([]byte(body), &r); err != nil {
+		return
+	}
+
+	network = r.Network
+	err = nil
+	return
+}
+
+func CreateNetwork(auth identity.Auth, opts NetworkOpts) (network Network, err error) {
+
+	req := gorequest.New()
+
+	url := fmt.Sprintf("%s/v2.0/networks",
+		auth.EndpointList["network"])
+
+	b, err := json.Marshal(networkReq{opts})
+	if err != nil {
+		return
+	}
+
+	resp, body, errs := req.Post(url).
+		Set("Content-Type", "application/json").
+		Set("Accept", "application/json").
+		Set("X-Auth-Token", auth.Access.Token.Id).
+		Send(string(b)).
+		End()
+
+	if errs != nil {
+		err = errs[len(errs)-1]
+		return
+	}
+
+	if err = openstack.CheckHttpResponseStatusCode(resp.StatusCode); err != nil {
+		return
+	}
+
+	var r = networkResp{}
+	if err = json.Unmarshal([]byte(body), &r); err != nil {
+		return
+	}
+
+	network = r.Network
+	err = nil
+	return
+}
+
+func UpdateNetwork(auth identity.Auth, id string, opts NetworkOpts) (network Network, err error) {
+
+	req := gorequest.New()
+
+	url := fmt.Sprintf("%s/v2.0/networks/%s",
+		auth.EndpointList["network"],
+		id)
+
+	b, err := json.Marshal(networkReq{opts})
+	if err != nil {
+		return
+	}
+
+	resp, body, errs := req.Put(url).
+		Set("Content-Type", "application/json").
+		Set("Accept", "application/json").
+		Set("X-Auth-Token", auth.Access.Token.Id).
+		Send(string(b)).
+		End()
+
+	if errs != nil {
+		err = errs[len(errs)-1]
+		return
+	}
+
+	if err = openstack.CheckHttpResponseStatusCode(resp.StatusCode); err != nil {
+		return
+	}
+
+	var r = networkResp{}
+	if err = json.Unmarshal([]byte(body), &r); err != nil {
+		return
+	}
+
+	network = r.Network
+	err = nil
+	return
+}
+
+func DeleteNetwork(auth identity.Auth, id string) (err error) {
+
+	req := gorequest.New()
+
+	url := fmt.Sprintf("%s/v2.0/networks/%s",
+		auth.EndpointList["network"],
+		id)
+
+	resp, _, errs := req.Delete(url).
+		Set("Content-Type", "application/json").
+		Set("Accept", "application/json").
+		Set("X-Auth-Token", auth.Access.Token.Id).
+		End()
+
+	if errs != nil {
+		err = errs[len(errs)-1]
+		return
+	}
+
+	if err = openstack.CheckHttpResponseStatusCode(resp.StatusCode); err != nil {
+		return
+	}
+
+	err = nil
+	return
+}
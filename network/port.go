@@ -3,7 +3,9 @@ package network
 import (
 	"encoding/json"
 	"fmt"
+	"net/url"
 
+	"github.com/gertd/go-openstack"
 	"github.com/gertd/go-openstack/identity"
 	"github.com/parnurzeal/gorequest"
 )
@@ -17,7 +19,7 @@ type portResp struct {
 }
 
 type portReq struct {
-	Port Port `json:"port,omitempty"`
+	Port PortOpts `json:"port"`
 }
 
 type ByName []Port
@@ -27,18 +29,33 @@ func (a ByName) Swap(i, j int)      { a[i], a[j] = a[j], a[i] }
 func (a ByName) Less(i, j int) bool { return a[i].Name < a[j].Name }
 
 type Port struct {
-	Id                  string    `json:"id,omitempty"`
-	Name                string    `json:"name,omitempty"`
-	Status              string    `json:"status,omitempty"`
-	AdminStateUp        bool      `json:"admin_state_up,omitempty"`
-	PortSecurityEnabled bool      `json:"port_security_enabled,omitempty"`
-	DeviceId            string    `json:"device_id,omitempty"`
-	DeviceOwner         string    `json:"device_owner,omitempty"`
-	NetworkId           string    `json:"network_id,omitempty"`
-	TenantId            string    `json:"tenant_id,omitempty"`
-	MacAddress          string    `json:"mac_address,omitempty"`
-	FixedIPs            []FixedIP `json:"fixed_ips,omitempty"`
-	SecurityGroups      []string  `json:"security_groups,omitempty"`
+	Id                  string                 `json:"id"`
+	Name                string                 `json:"name"`
+	Description         string                 `json:"description"`
+	Status              string                 `json:"status"`
+	AdminStateUp        bool                   `json:"admin_state_up"`
+	PortSecurityEnabled bool                   `json:"port_security_enabled"`
+	DeviceId            string                 `json:"device_id"`
+	DeviceOwner         string                 `json:"device_owner"`
+	NetworkId           string                 `json:"network_id"`
+	TenantId            string                 `json:"tenant_id"`
+	ProjectId           string                 `json:"project_id"`
+	MacAddress          string                 `json:"mac_address"`
+	FixedIPs            []FixedIP              `json:"fixed_ips"`
+	SecurityGroups      []string               `json:"security_groups"`
+	AllowedAddressPairs []AddressPair          `json:"allowed_address_pairs"`
+	ExtraDHCPOpts       []ExtraDHCPOpt         `json:"extra_dhcp_opts"`
+	HostId              string                 `json:"binding:host_id"`
+	VNICType            string                 `json:"binding:vnic_type"`
+	VIFType             string                 `json:"binding:vif_type"`
+	VIFDetails          map[string]interface{} `json:"binding:vif_details"`
+	Profile             map[string]interface{} `json:"binding:profile"`
+	DnsName             string                 `json:"dns_name"`
+	DnsAssignment       []DnsAssignment        `json:"dns_assignment"`
+	QosPolicyId         string                 `json:"qos_policy_id"`
+	Tags                []string               `json:"tags"`
+	CreatedAt           string                 `json:"created_at"`
+	UpdatedAt           string                 `json:"updated_at"`
 }
 
 type FixedIP struct {
@@ -46,14 +63,122 @@ type FixedIP struct {
 	IPAddress string `json:"ip_address,omitempty"`
 }
 
+// AddressPair allows traffic from an additional address on the port, such
+// as a VRRP virtual IP. An empty MacAddress means the port MAC address.
+type AddressPair struct {
+	IPAddress  string `json:"ip_address"`
+	MacAddress string `json:"mac_address,omitempty"`
+}
+
+type ExtraDHCPOpt struct {
+	OptName   string `json:"opt_name"`
+	OptValue  string `json:"opt_value"`
+	IPVersion int    `json:"ip_version,omitempty"`
+}
+
+type DnsAssignment struct {
+	Hostname  string `json:"hostname"`
+	IPAddress string `json:"ip_address"`
+	FQDN      string `json:"fqdn"`
+}
+
+// PortOpts is used to create and update ports, nil and empty fields are not
+// sent. The slices are pointers so that a list can be cleared by pointing
+// to an empty slice. The binding attributes require admin rights. A
+// QosPolicyId pointing to an empty string detaches the QoS policy.
+type PortOpts struct {
+	Name                *string                `json:"name,omitempty"`
+	Description         *string                `json:"description,omitempty"`
+	NetworkId           string                 `json:"network_id,omitempty"`
+	TenantId            string                 `json:"tenant_id,omitempty"`
+	AdminStateUp        *bool                  `json:"admin_state_up,omitempty"`
+	PortSecurityEnabled *bool                  `json:"port_security_enabled,omitempty"`
+	DeviceId            *string                `json:"device_id,omitempty"`
+	DeviceOwner         *string                `json:"device_owner,omitempty"`
+	MacAddress          string                 `json:"mac_address,omitempty"`
+	FixedIPs            *[]FixedIP             `json:"fixed_ips,omitempty"`
+	SecurityGroups      *[]string              `json:"security_groups,omitempty"`
+	AllowedAddressPairs *[]AddressPair         `json:"allowed_address_pairs,omitempty"`
+	ExtraDHCPOpts       *[]ExtraDHCPOpt        `json:"extra_dhcp_opts,omitempty"`
+	HostId              *string                `json:"binding:host_id,omitempty"`
+	VNICType            string                 `json:"binding:vnic_type,omitempty"`
+	Profile             map[string]interface{} `json:"binding:profile,omitempty"`
+	DnsName             *string                `json:"dns_name,omitempty"`
+	QosPolicyId         *string                `json:"qos_policy_id,omitempty"`
+}
+
+func (opts PortOpts) MarshalJSON() (b []byte, err error) {
+
+	type portOpts PortOpts
+
+	b, err = json.Marshal(portOpts(opts))
+	if err != nil || opts.QosPolicyId == nil || len(*opts.QosPolicyId) > 0 {
+		return
+	}
+
+	var attrs map[string]interface{}
+	if err = json.Unmarshal(b, &attrs); err != nil {
+		return
+	}
+
+	attrs["qos_policy_id"] = nil
+
+	return json.Marshal(attrs)
+}
+
+// PortFilter narrows GetPortsFiltered, empty fields are not sent. SubnetId
+// and IPAddress match the fixed IPs of the port.
+type PortFilter struct {
+	Name        string
+	DeviceId    string
+	DeviceOwner string
+	NetworkId   string
+	MacAddress  string
+	Status      string
+	SubnetId    string
+	IPAddress   string
+}
+
 func GetPorts(auth identity.Auth) (ports []Port, err error) {
 
+	return GetPortsFiltered(auth, PortFilter{})
+}
+
+func GetPortsFiltered(auth identity.Auth, filter PortFilter) (ports []Port, err error) {
+
 	req := gorequest.New()
 
-	url := fmt.Sprintf("%s/v2.0/ports",
-		auth.EndpointList["network"])
+	query := url.Values{}
+	if len(filter.Name) > 0 {
+		query.Set("name", filter.Name)
+	}
+	if len(filter.DeviceId) > 0 {
+		query.Set("device_id", filter.DeviceId)
+	}
+	if len(filter.DeviceOwner) > 0 {
+		query.Set("device_owner", filter.DeviceOwner)
+	}
+	if len(filter.NetworkId) > 0 {
+		query.Set("network_id", filter.NetworkId)
+	}
+	if len(filter.MacAddress) > 0 {
+		query.Set("mac_address", filter.MacAddress)
+	}
+	if len(filter.Status) > 0 {
+		query.Set("status", filter.Status)
+	}
+	if len(filter.SubnetId) > 0 {
+		query.Add("fixed_ips", "subnet_id="+filter.SubnetId)
+	}
+	if len(filter.IPAddress) > 0 {
+		query.Add("fixed_ips", "ip_address="+filter.IPAddress)
+	}
+
+	reqUrl := fmt.Sprintf("%s/v2.0/ports?%s",
+		auth.EndpointList["network"],
+		query.Encode())
 
-	_, body, errs := req.Get(url).
+	resp, body, errs := req.Get(reqUrl).
 		Set("Content-Type", "application/json").
 		Set("Accept", "application/json").
 		Set("X-Auth-Token", auth.Access.Token.Id).
@@ -64,6 +189,10 @@ func GetPorts(auth identity.Auth) (ports []Port, err error) {
 		return
 	}
 
+	if err = openstack.CheckHttpResponseStatusCode(resp.StatusCode); err != nil {
+		return
+	}
+
 	var p = portsResp{}
 	if err = json.Unmarshal([]byte(body), &p); err != nil {
 		return
@@ -74,16 +203,18 @@ func GetPorts(auth identity.Auth) (ports []Port, err error) {
 	return
 }
 
-func GetPort(url string, token identity.Token, id string) (port Port, err error) {
+func GetPort(auth identity.Auth, id string) (port Port, err error) {
 
 	req := gorequest.New()
 
-	reqUrl := fmt.Sprintf("%s/v2.0/ports/%s", url, id)
+	reqUrl := fmt.Sprintf("%s/v2.0/ports/%s",
+		auth.EndpointList["network"],
+		id)
 
-	_, body, errs := req.Get(reqUrl).
+	resp, body, errs := req.Get(reqUrl).
 		Set("Content-Type", "application/json").
 		Set("Accept", "application/json").
-		Set("X-Auth-Token", token.Id).
+		Set("X-Auth-Token", auth.Access.Token.Id).
 		End()
 
 	if errs != nil {
@@ -91,10 +222,16 @@ func GetPort(url string, token identity.Token, id string) (port Port, err error)
 		return
 	}
 
-	if err = json.Unmarshal([]byte(body), &port); err != nil {
+	if err = openstack.CheckHttpResponseStatusCode(resp.StatusCode); err != nil {
+		return
+	}
+
+	var r = portResp{}
+	if err = json.Unmarshal([]byte(body), &r); err != nil {
 		return
 	}
 
+	port = r.Port
 	err = nil
 	return
 }
@@ -122,21 +259,19 @@ func DeletePort(auth identity.Auth, id string) (err error) {
 	return
 }
 
-func CreatePort(auth identity.Auth, port Port) (resPort Port, err error) {
+func CreatePort(auth identity.Auth, opts PortOpts) (resPort Port, err error) {
 
 	req := gorequest.New()
 
 	url := fmt.Sprintf("%s/v2.0/ports",
 		auth.EndpointList["network"])
 
-	reqPort := portReq{port}
-
-	b, err := json.Marshal(reqPort)
+	b, err := json.Marshal(portReq{opts})
 	if err != nil {
-		fmt.Println(err.Error())
+		return
 	}
 
-	_, body, errs := req.Post(url).
+	resp, body, errs := req.Post(url).
 		Set("Content-Type", "application/json").
 		Set("Accept", "application/json").
 		Set("X-Auth-Token", auth.Access.Token.Id).
@@ -148,6 +283,10 @@ func CreatePort(auth identity.Auth, port Port) (resPort Port, err error) {
 		return
 	}
 
+	if err = openstack.CheckHttpResponseStatusCode(resp.StatusCode); err != nil {
+		return
+	}
+
 	var portResp = portResp{}
 	if err = json.Unmarshal([]byte(body), &portResp); err != nil {
 		return
@@ -157,3 +296,42 @@ func CreatePort(auth identity.Auth, port Port) (resPort Port, err error) {
 	resPort = portResp.Port
 	return
 }
+
+func UpdatePort(auth identity.Auth, id string, opts PortOpts) (port Port, err error) {
+
+	req := gorequest.New()
+
+	url := fmt.Sprintf("%s/v2.0/ports/%s",
+		auth.EndpointList["network"],
+		id)
+
+	b, err := json.Marshal(portReq{opts})
+	if err != nil {
+		return
+	}
+
+	resp, body, errs := req.Put(url).
+		Set("Content-Type", "application/json").
+		Set("Accept", "application/json").
+		Set("X-Auth-Token", auth.Access.Token.Id).
+		Send(string(b)).
+		End()
+
+	if errs != nil {
+		err = errs[len(errs)-1]
+		return
+	}
+
+	if err = openstack.CheckHttpResponseStatusCode(resp.StatusCode); err != nil {
+		return
+	}
+
+	var r = portResp{}
+	if err = json.Unmarshal([]byte(body), &r); err != nil {
+		return
+	}
+
+	port = r.Port
+	err = nil
+	return
+}
@@ -0,0 +1,207 @@
+package network
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/gertd/go-openstack"
+	"github.com/gertd/go-openstack/identity"
+	"github.com/parnurzeal/gorequest"
+)
+
+type quotaResp struct {
+	Quota Quota `json:"quota"`
+}
+
+type quotaDetailsResp struct {
+	Quota QuotaDetails `json:"quota"`
+}
+
+type quotaReq struct {
+	Quota QuotaOpts `json:"quota"`
+}
+
+// A limit of -1 means unlimited.
+type Quota struct {
+	Network           int `json:"network"`
+	Subnet            int `json:"subnet"`
+	SubnetPool        int `json:"subnetpool"`
+	Port              int `json:"port"`
+	Router            int `json:"router"`
+	FloatingIP        int `json:"floatingip"`
+	SecurityGroup     int `json:"security_group"`
+	SecurityGroupRule int `json:"security_group_rule"`
+	RBACPolicy        int `json:"rbac_policy"`
+}
+
+// QuotaOpts is used to update quotas, nil fields are not sent.
+type QuotaOpts struct {
+	Network           *int `json:"network,omitempty"`
+	Subnet            *int `json:"subnet,omitempty"`
+	SubnetPool        *int `json:"subnetpool,omitempty"`
+	Port              *int `json:"port,omitempty"`
+	Router            *int `json:"router,omitempty"`
+	FloatingIP        *int `json:"floatingip,omitempty"`
+	SecurityGroup     *int `json:"security_group,omitempty"`
+	SecurityGroupRule *int `json:"security_group_rule,omitempty"`
+	RBACPolicy        *int `json:"rbac_policy,omitempty"`
+}
+
+type QuotaDetails struct {
+	Network           QuotaDetail `json:"network"`
+	Subnet            QuotaDetail `json:"subnet"`
+	SubnetPool        QuotaDetail `json:"subnetpool"`
+	Port              QuotaDetail `json:"port"`
+	Router            QuotaDetail `json:"router"`
+	FloatingIP        QuotaDetail `json:"floatingip"`
+	SecurityGroup     QuotaDetail `json:"security_group"`
+	SecurityGroupRule QuotaDetail `json:"security_group_rule"`
+	RBACPolicy        QuotaDetail `json:"rbac_policy"`
+}
+
+type QuotaDetail struct {
+	Used     int `json:"used"`
+	Reserved int `json:"reserved"`
+	Limit    int `json:"limit"`
+}
+
+func GetQuota(auth identity.Auth, projectId string) (quota Quota, err error) {
+
+	return getQuota(auth, fmt.Sprintf("%s/v2.0/quotas/%s", auth.EndpointList["network"], projectId))
+}
+
+func GetDefaultQuota(auth identity.Auth, projectId string) (quota Quota, err error) {
+
+	return getQuota(auth, fmt.Sprintf("%s/v2.0/quotas/%s/default", auth.EndpointList["network"], projectId))
+}
+
+func getQuota(auth identity.Auth, url string) (quota Quota, err error) {
+
+	req := gorequest.New()
+
+	resp, body, errs := req.Get(url).
+		Set("Content-Type", "application/json").
+		Set("Accept", "application/json").
+		Set("X-Auth-Token", auth.Access.Token.Id).
+		End()
+
+	if errs != nil {
+		err = errs[len(errs)-1]
+		return
+	}
+
+	if err = openstack.CheckHttpResponseStatusCode(resp.StatusCode); err != nil {
+		return
+	}
+
+	var r = quotaResp{}
+	if err = json.Unmarshal([]byte(body), &r); err != nil {
+		return
+	}
+
+	quota = r.Quota
+	err = nil
+	return
+}
+
+// GetQuotaDetails returns the usage of every resource along with its
+// limit, it requires the quota_details extension.
+func GetQuotaDetails(auth identity.Auth, projectId string) (details QuotaDetails, err error) {
+
+	req := gorequest.New()
+
+	url := fmt.Sprintf("%s/v2.0/quotas/%s/details.json",
+		auth.EndpointList["network"],
+		projectId)
+
+	resp, body, errs := req.Get(url).
+		Set("Content-Type", "application/json").
+		Set("Accept", "application/json").
+		Set("X-Auth-Token", auth.Access.Token.Id).
+		End()
+
+	if errs != nil {
+		err = errs[len(errs)-1]
+		return
+	}
+
+	if err = openstack.CheckHttpResponseStatusCode(resp.StatusCode); err != nil {
+		return
+	}
+
+	var r = quotaDetailsResp{}
+	if err = json.Unmarshal([]byte(body), &r); err != nil {
+		return
+	}
+
+	details = r.Quota
+	err = nil
+	return
+}
+
+func UpdateQuota(auth identity.Auth, projectId string, opts QuotaOpts) (quota Quota, err error) {
+
+	req := gorequest.New()
+
+	url := fmt.Sprintf("%s/v2.0/quotas/%s",
+		auth.EndpointList["network"],
+		projectId)
+
+	b, err := json.Marshal(quotaReq{opts})
+	if err != nil {
+		return
+	}
+
+	resp, body, errs := req.Put(url).
+		Set("Content-Type", "application/json").
+		Set("Accept", "application/json").
+		Set("X-Auth-Token", auth.Access.Token.Id).
+		Send(string(b)).
+		End()
+
+	if errs != nil {
+		err = errs[len(errs)-1]
+		return
+	}
+
+	if err = openstack.CheckHttpResponseStatusCode(resp.StatusCode); err != nil {
+		return
+	}
+
+	var r = quotaResp{}
+	if err = json.Unmarshal([]byte(body), &r); err != nil {
+		return
+	}
+
+	quota = r.Quota
+	err = nil
+	return
+}
+
+// ResetQuota reverts the quotas of the project to the defaults.
+func ResetQuota(auth identity.Auth, projectId string) (err error) {
+
+	req := gorequest.New()
+
+	url := fmt.Sprintf("%s/v2.0/quotas/%s",
+		auth.EndpointList["network"],
+		projectId)
+
+	resp, _, errs := req.Delete(url).
+		Set("Content-Type", "application/json").
+		Set("Accept", "application/json").
+		Set("X-Auth-Token", auth.Access.Token.Id).
+		End()
+
+	if errs != nil {
+		err = errs[len(errs)-1]
+		return
+	}
+
+	if err = openstack.CheckHttpResponseStatusCode(resp.StatusCode); err != nil {
+		return
+	}
+
+	err = nil
+	return
+}
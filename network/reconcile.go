@@ -0,0 +1,170 @@
+package network
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"strings"
+
+	"github.com/gertd/go-openstack/identity"
+)
+
+// ReconcileResult reports what ReconcileSecurityGroup changed.
+type ReconcileResult struct {
+	Group   SecurityGroup
+	Created bool
+	Added   []SecurityGroupRule
+	Deleted []SecurityGroupRule
+}
+
+var protocolNames = map[string]string{
+	"1":  "icmp",
+	"6":  "tcp",
+	"17": "udp",
+	"58": "ipv6-icmp",
+}
+
+// ruleKey identifies what a rule matches, the description and the IDs are
+// ignored.
+func ruleKey(direction, etherType, protocol string, min, max *int, prefix, group, addressGroup string) string {
+
+	// Neutron stores rules created without an ethertype as IPv4
+	etherType = strings.ToLower(etherType)
+	if etherType == "" {
+		etherType = "ipv4"
+	}
+
+	protocol = strings.ToLower(protocol)
+	if name, ok := protocolNames[protocol]; ok {
+		protocol = name
+	}
+	if protocol == "any" {
+		protocol = ""
+	}
+	if protocol == "icmpv6" {
+		protocol = "ipv6-icmp"
+	}
+
+	if _, ipNet, err := net.ParseCIDR(prefix); err == nil {
+		prefix = ipNet.String()
+	}
+	if prefix == "0.0.0.0/0" || prefix == "::/0" {
+		prefix = ""
+	}
+
+	port := func(p *int) string {
+		if p == nil {
+			return ""
+		}
+		return fmt.Sprintf("%d", *p)
+	}
+
+	return strings.Join([]string{
+		strings.ToLower(direction),
+		etherType,
+		protocol,
+		port(min),
+		port(max),
+		prefix,
+		group,
+		addressGroup,
+	}, "|")
+}
+
+func (r SecurityGroupRule) key() string {
+
+	return ruleKey(r.Direction, r.EtherType, r.Protocol, r.PortRangeMin, r.PortRangeMax, r.RemoteIPPrefix, r.RemoteGroupId, r.RemoteAddressGroupId)
+}
+
+func (r SecurityGroupRuleOpts) key() string {
+
+	return ruleKey(r.Direction, r.EtherType, r.Protocol, r.PortRangeMin, r.PortRangeMax, r.RemoteIPPrefix, r.RemoteGroupId, r.RemoteAddressGroupId)
+}
+
+// DiffSecurityGroupRules computes the rules to add and the rules to delete
+// so that current matches desired. Rules are compared on what they match,
+// so a desired rule which only differs by its description is left alone.
+func DiffSecurityGroupRules(current []SecurityGroupRule, desired []SecurityGroupRuleOpts) (add []SecurityGroupRuleOpts, remove []SecurityGroupRule) {
+
+	wanted := map[string]bool{}
+	for _, v := range desired {
+		wanted[v.key()] = true
+	}
+
+	existing := map[string]bool{}
+	for _, v := range current {
+		k := v.key()
+		if !wanted[k] || existing[k] {
+			remove = append(remove, v)
+			continue
+		}
+		existing[k] = true
+	}
+
+	for _, v := range desired {
+		k := v.key()
+		if existing[k] {
+			continue
+		}
+		existing[k] = true
+		add = append(add, v)
+	}
+
+	return add, remove
+}
+
+// ReconcileSecurityGroup converges the rules of the security group with the
+// given name to desired, creating the group when it does not exist. New
+// rules are added before stale ones are deleted so that allowed traffic is
+// not interrupted.
+func ReconcileSecurityGroup(auth identity.Auth, name string, desired []SecurityGroupRuleOpts) (result ReconcileResult, err error) {
+
+	groups, err := GetSecurityGroupsByName(auth, name)
+	if err != nil {
+		return
+	}
+
+	switch len(groups) {
+	case 0:
+		if result.Group, err = CreateSecurityGroup(auth, SecurityGroupOpts{Name: &name}); err != nil {
+			return
+		}
+		result.Created = true
+	case 1:
+		result.Group = groups[0]
+	default:
+		err = errors.New(fmt.Sprintf("security group %s multiple entries found", name))
+		return
+	}
+
+	current, err := GetSecurityGroupRules(auth, result.Group.Id)
+	if err != nil {
+		return
+	}
+
+	add, remove := DiffSecurityGroupRules(current, desired)
+
+	for _, v := range add {
+		v.SecurityGroupId = result.Group.Id
+		rule, e := CreateSecurityGroupRule(auth, v)
+		if e != nil {
+			err = e
+			return
+		}
+		result.Added = append(result.Added, rule)
+	}
+
+	for _, v := range remove {
+		if err = DeleteSecurityGroupRule(auth, v.Id); err != nil {
+			return
+		}
+		result.Deleted = append(result.Deleted, v)
+	}
+
+	if result.Group, err = GetSecurityGroup(auth, result.Group.Id); err != nil {
+		return
+	}
+
+	err = nil
+	return
+}
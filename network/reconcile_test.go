@@ -0,0 +1,94 @@
+package network
+
+import (
+	"testing"
+)
+
+func intPtr(i int) *int {
+
+	return &i
+}
+
+func TestDiffSecurityGroupRules(t *testing.T) {
+
+	ssh := SecurityGroupRule{Id: "ssh", Direction: "ingress", EtherType: "IPv4", Protocol: "tcp", PortRangeMin: intPtr(22), PortRangeMax: intPtr(22)}
+	anyIn := SecurityGroupRule{Id: "any", Direction: "ingress", EtherType: "IPv4", RemoteIPPrefix: "0.0.0.0/0"}
+	v6 := SecurityGroupRule{Id: "v6", Direction: "ingress", EtherType: "IPv6", Protocol: "ipv6-icmp"}
+
+	tests := []struct {
+		name    string
+		current []SecurityGroupRule
+		desired []SecurityGroupRuleOpts
+		add     int
+		remove  []string
+	}{
+		{
+			name:    "default ethertype matches IPv4",
+			current: []SecurityGroupRule{ssh},
+			desired: []SecurityGroupRuleOpts{{Direction: "ingress", Protocol: "tcp", PortRangeMin: intPtr(22), PortRangeMax: intPtr(22)}},
+		},
+		{
+			name:    "default ethertype does not match IPv6",
+			current: []SecurityGroupRule{v6},
+			desired: []SecurityGroupRuleOpts{{Direction: "ingress", Protocol: "ipv6-icmp"}},
+			add:     1,
+			remove:  []string{"v6"},
+		},
+		{
+			name:    "protocol number alias",
+			current: []SecurityGroupRule{ssh},
+			desired: []SecurityGroupRuleOpts{{Direction: "ingress", EtherType: "IPv4", Protocol: "6", PortRangeMin: intPtr(22), PortRangeMax: intPtr(22)}},
+		},
+		{
+			name:    "icmpv6 alias",
+			current: []SecurityGroupRule{v6},
+			desired: []SecurityGroupRuleOpts{{Direction: "ingress", EtherType: "IPv6", Protocol: "icmpv6"}},
+		},
+		{
+			name:    "any protocol and 0.0.0.0/0 match an empty rule",
+			current: []SecurityGroupRule{{Id: "empty", Direction: "ingress", EtherType: "IPv4"}},
+			desired: []SecurityGroupRuleOpts{{Direction: "ingress", Protocol: "any", RemoteIPPrefix: "0.0.0.0/0"}},
+		},
+		{
+			name:    "empty prefix matches 0.0.0.0/0",
+			current: []SecurityGroupRule{anyIn},
+			desired: []SecurityGroupRuleOpts{{Direction: "ingress"}},
+		},
+		{
+			name:    "changed port",
+			current: []SecurityGroupRule{ssh},
+			desired: []SecurityGroupRuleOpts{{Direction: "ingress", Protocol: "tcp", PortRangeMin: intPtr(80), PortRangeMax: intPtr(80)}},
+			add:     1,
+			remove:  []string{"ssh"},
+		},
+		{
+			name:    "duplicate current rule",
+			current: []SecurityGroupRule{ssh, {Id: "dup", Direction: "ingress", EtherType: "ipv4", Protocol: "TCP", PortRangeMin: intPtr(22), PortRangeMax: intPtr(22)}},
+			desired: []SecurityGroupRuleOpts{{Direction: "ingress", Protocol: "tcp", PortRangeMin: intPtr(22), PortRangeMax: intPtr(22)}},
+			remove:  []string{"dup"},
+		},
+		{
+			name:    "duplicate desired rule",
+			desired: []SecurityGroupRuleOpts{{Direction: "egress"}, {Direction: "egress", EtherType: "IPv4", Protocol: "any"}},
+			add:     1,
+		},
+	}
+
+	for _, tt := range tests {
+		add, remove := DiffSecurityGroupRules(tt.current, tt.desired)
+
+		if len(add) != tt.add {
+			t.Errorf("%s: added %d rules, want %d", tt.name, len(add), tt.add)
+		}
+
+		if len(remove) != len(tt.remove) {
+			t.Errorf("%s: removed %d rules, want %d", tt.name, len(remove), len(tt.remove))
+			continue
+		}
+		for i, v := range remove {
+			if v.Id != tt.remove[i] {
+				t.Errorf("%s: removed %s, want %s", tt.name, v.Id, tt.remove[i])
+			}
+		}
+	}
+}
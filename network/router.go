@@ -0,0 +1,376 @@
+package network
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/gertd/go-openstack"
+	"github.com/gertd/go-openstack/identity"
+	"github.com/parnurzeal/gorequest"
+)
+
+type routersResp struct {
+	Routers []Router `json:"routers"`
+}
+
+type routerResp struct {
+	Router Router `json:"router"`
+}
+
+type routerReq struct {
+	Router RouterOpts `json:"router"`
+}
+
+type routerRoutesReq struct {
+	Router routerRoutes `json:"router"`
+}
+
+type routerRoutes struct {
+	Routes []HostRoute `json:"routes"`
+}
+
+// ExternalGatewayInfo is nil when the router has no gateway.
+type Router struct {
+	Id                    string       `json:"id"`
+	Name                  string       `json:"name"`
+	Description           string       `json:"description"`
+	Status                string       `json:"status"`
+	AdminStateUp          bool         `json:"admin_state_up"`
+	TenantId              string       `json:"tenant_id"`
+	ProjectId             string       `json:"project_id"`
+	ExternalGatewayInfo   *GatewayInfo `json:"external_gateway_info"`
+	Routes                []HostRoute  `json:"routes"`
+	HA                    bool         `json:"ha"`
+	Distributed           bool         `json:"distributed"`
+	AvailabilityZones     []string     `json:"availability_zones"`
+	AvailabilityZoneHints []string     `json:"availability_zone_hints"`
+	Tags                  []string     `json:"tags"`
+	CreatedAt             string       `json:"created_at"`
+	UpdatedAt             string       `json:"updated_at"`
+}
+
+type GatewayInfo struct {
+	NetworkId        string    `json:"network_id"`
+	EnableSNAT       *bool     `json:"enable_snat,omitempty"`
+	ExternalFixedIPs []FixedIP `json:"external_fixed_ips,omitempty"`
+}
+
+// RouterOpts is used to create and update routers, nil and empty fields are
+// not sent. An ExternalGatewayInfo with an empty NetworkId clears the
+// gateway. HA and Distributed require admin rights.
+type RouterOpts struct {
+	Name                  *string      `json:"name,omitempty"`
+	Description           *string      `json:"description,omitempty"`
+	TenantId              string       `json:"tenant_id,omitempty"`
+	AdminStateUp          *bool        `json:"admin_state_up,omitempty"`
+	ExternalGatewayInfo   *GatewayInfo `json:"external_gateway_info,omitempty"`
+	Routes                *[]HostRoute `json:"routes,omitempty"`
+	HA                    *bool        `json:"ha,omitempty"`
+	Distributed           *bool        `json:"distributed,omitempty"`
+	AvailabilityZoneHints []string     `json:"availability_zone_hints,omitempty"`
+}
+
+// RouterInterfaceOpts names either the subnet or the port to attach.
+type RouterInterfaceOpts struct {
+	SubnetId string `json:"subnet_id,omitempty"`
+	PortId   string `json:"port_id,omitempty"`
+}
+
+type RouterInterface struct {
+	Id        string   `json:"id"`
+	SubnetId  string   `json:"subnet_id"`
+	SubnetIds []string `json:"subnet_ids"`
+	PortId    string   `json:"port_id"`
+	NetworkId string   `json:"network_id"`
+	TenantId  string   `json:"tenant_id"`
+}
+
+func (opts RouterOpts) MarshalJSON() (b []byte, err error) {
+
+	type routerOpts RouterOpts
+
+	b, err = json.Marshal(routerOpts(opts))
+	if err != nil || opts.ExternalGatewayInfo == nil || len(opts.ExternalGatewayInfo.NetworkId) > 0 {
+		return
+	}
+
+	var attrs map[string]interface{}
+	if err = json.Unmarshal(b, &attrs); err != nil {
+		return
+	}
+
+	attrs["external_gateway_info"] = nil
+
+	return json.Marshal(attrs)
+}
+
+func GetRouters(auth identity.Auth) (routers []Router, err error) {
+
+	req := gorequest.New()
+
+	url := fmt.Sprintf("%s/v2.0/routers",
+		auth.EndpointList["network"])
+
+	resp, body, errs := req.Get(url).
+		Set("Content-Type", "application/json").
+		Set("Accept", "application/json").
+		Set("X-Auth-Token", auth.Access.Token.Id).
+		End()
+
+	if errs != nil {
+		err = errs[len(errs)-1]
+		return
+	}
+
+	if err = openstack.CheckHttpResponseStatusCode(resp.StatusCode); err != nil {
+		return
+	}
+
+	var r = routersResp{}
+	if err = json.Unmarshal([]byte(body), &r); err != nil {
+		return
+	}
+
+	routers = r.Routers
+	err = nil
+	return
+}
+
+func GetRouter(auth identity.Auth, id string) (router Router, err error) {
+
+	req := gorequest.New()
+
+	url := fmt.Sprintf("%s/v2.0/routers/%s",
+		auth.EndpointList["network"],
+		id)
+
+	resp, body, errs := req.Get(url).
+		Set("Content-Type", "application/json").
+		Set("Accept", "application/json").
+		Set("X-Auth-Token", auth.Access.Token.Id).
+		End()
+
+	if errs != nil {
+		err = errs[len(errs)-1]
+		return
+	}
+
+	if err = openstack.CheckHttpResponseStatusCode(resp.StatusCode); err != nil {
+		return
+	}
+
+	var r = routerResp{}
+	if err = json.Unmarshal([]byte(body), &r); err != nil {
+		return
+	}
+
+	router = r.Router
+	err = nil
+	return
+}
+
+func CreateRouter(auth identity.Auth, opts RouterOpts) (router Router, err error) {
+
+	req := gorequest.New()
+
+	url := fmt.Sprintf("%s/v2.0/routers",
+		auth.EndpointList["network"])
+
+	b, err := json.Marshal(routerReq{opts})
+	if err != nil {
+		return
+	}
+
+	resp, body, errs := req.Post(url).
+		Set("Content-Type", "application/json").
+		Set("Accept", "application/json").
+		Set("X-Auth-Token", auth.Access.Token.Id).
+		Send(string(b)).
+		End()
+
+	if errs != nil {
+		err = errs[len(errs)-1]
+		return
+	}
+
+	if err = openstack.CheckHttpResponseStatusCode(resp.StatusCode); err != nil {
+		return
+	}
+
+	var r = routerResp{}
+	if err = json.Unmarshal([]byte(body), &r); err != nil {
+		return
+	}
+
+	router = r.Router
+	err = nil
+	return
+}
+
+func UpdateRouter(auth identity.Auth, id string, opts RouterOpts) (router Router, err error) {
+
+	req := gorequest.New()
+
+	url := fmt.Sprintf("%s/v2.0/routers/%s",
+		auth.EndpointList["network"],
+		id)
+
+	b, err := json.Marshal(routerReq{opts})
+	if err != nil {
+		return
+	}
+
+	resp, body, errs := req.Put(url).
+		Set("Content-Type", "application/json").
+		Set("Accept", "application/json").
+		Set("X-Auth-Token", auth.Access.Token.Id).
+		Send(string(b)).
+		End()
+
+	if errs != nil {
+		err = errs[len(errs)-1]
+		return
+	}
+
+	if err = openstack.CheckHttpResponseStatusCode(resp.StatusCode); err != nil {
+		return
+	}
+
+	var r = routerResp{}
+	if err = json.Unmarshal([]byte(body), &r); err != nil {
+		return
+	}
+
+	router = r.Router
+	err = nil
+	return
+}
+
+func DeleteRouter(auth identity.Auth, id string) (err error) {
+
+	req := gorequest.New()
+
+	url := fmt.Sprintf("%s/v2.0/routers/%s",
+		auth.EndpointList["network"],
+		id)
+
+	resp, _, errs := req.Delete(url).
+		Set("Content-Type", "application/json").
+		Set("Accept", "application/json").
+		Set("X-Auth-Token", auth.Access.Token.Id).
+		End()
+
+	if errs != nil {
+		err = errs[len(errs)-1]
+		return
+	}
+
+	if err = openstack.CheckHttpResponseStatusCode(resp.StatusCode); err != nil {
+		return
+	}
+
+	err = nil
+	return
+}
+
+func AddRouterInterface(auth identity.Auth, id string, opts RouterInterfaceOpts) (routerInterface RouterInterface, err error) {
+
+	return routerInterfaceReq(auth, id, "add_router_interface", opts)
+}
+
+func RemoveRouterInterface(auth identity.Auth, id string, opts RouterInterfaceOpts) (routerInterface RouterInterface, err error) {
+
+	return routerInterfaceReq(auth, id, "remove_router_interface", opts)
+}
+
+func routerInterfaceReq(auth identity.Auth, id string, action string, opts RouterInterfaceOpts) (routerInterface RouterInterface, err error) {
+
+	req := gorequest.New()
+
+	url := fmt.Sprintf("%s/v2.0/routers/%s/%s",
+		auth.EndpointList["network"],
+		id,
+		action)
+
+	b, err := json.Marshal(opts)
+	if err != nil {
+		return
+	}
+
+	resp, body, errs := req.Put(url).
+		Set("Content-Type", "application/json").
+		Set("Accept", "application/json").
+		Set("X-Auth-Token", auth.Access.Token.Id).
+		Send(string(b)).
+		End()
+
+	if errs != nil {
+		err = errs[len(errs)-1]
+		return
+	}
+
+	if err = openstack.CheckHttpResponseStatusCode(resp.StatusCode); err != nil {
+		return
+	}
+
+	if err = json.Unmarshal([]byte(body), &routerInterface); err != nil {
+		return
+	}
+
+	err = nil
+	return
+}
+
+// AddRouterRoutes adds static routes to the router without replacing the
+// existing ones, it requires the extraroute-atomic extension.
+func AddRouterRoutes(auth identity.Auth, id string, routes []HostRoute) (router Router, err error) {
+
+	return updateRouterRoutes(auth, id, "add_extraroutes", routes)
+}
+
+// RemoveRouterRoutes removes static routes from the router, it requires the
+// extraroute-atomic extension.
+func RemoveRouterRoutes(auth identity.Auth, id string, routes []HostRoute) (router Router, err error) {
+
+	return updateRouterRoutes(auth, id, "remove_extraroutes", routes)
+}
+
+func updateRouterRoutes(auth identity.Auth, id string, action string, routes []HostRoute) (router Router, err error) {
+
+	req := gorequest.New()
+
+	url := fmt.Sprintf("%s/v2.0/routers/%s/%s",
+		auth.EndpointList["network"],
+		id,
+		action)
+
+	b, err := json.Marshal(routerRoutesReq{routerRoutes{routes}})
+	if err != nil {
+		return
+	}
+
+	resp, body, errs := req.Put(url).
+		Set("Content-Type", "application/json").
+		Set("Accept", "application/json").
+		Set("X-Auth-Token", auth.Access.Token.Id).
+		Send(string(b)).
+		End()
+
+	if errs != nil {
+		err = errs[len(errs)-1]
+		return
+	}
+
+	if err = openstack.CheckHttpResponseStatusCode(resp.StatusCode); err != nil {
+		return
+	}
+
+	var r = routerResp{}
+	if err = json.Unmarshal([]byte(body), &r); err != nil {
+		return
+	}
+
+	router = r.Router
+	err = nil
+	return
+}
@@ -0,0 +1,412 @@
+package network
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/url"
+
+	"github.com/gertd/go-openstack"
+	"github.com/gertd/go-openstack/identity"
+	"github.com/parnurzeal/gorequest"
+)
+
+type securityGroupsResp struct {
+	SecurityGroups []SecurityGroup `json:"security_groups"`
+}
+
+type securityGroupResp struct {
+	SecurityGroup SecurityGroup `json:"security_group"`
+}
+
+type securityGroupReq struct {
+	SecurityGroup SecurityGroupOpts `json:"security_group"`
+}
+
+type securityGroupRulesResp struct {
+	SecurityGroupRules []SecurityGroupRule `json:"security_group_rules"`
+}
+
+type securityGroupRuleResp struct {
+	SecurityGroupRule SecurityGroupRule `json:"security_group_rule"`
+}
+
+type securityGroupRuleReq struct {
+	SecurityGroupRule SecurityGroupRuleOpts `json:"security_group_rule"`
+}
+
+type SecurityGroup struct {
+	Id          string              `json:"id"`
+	Name        string              `json:"name"`
+	Description string              `json:"description"`
+	TenantId    string              `json:"tenant_id"`
+	ProjectId   string              `json:"project_id"`
+	Stateful    bool                `json:"stateful"`
+	Rules       []SecurityGroupRule `json:"security_group_rules"`
+	Tags        []string            `json:"tags"`
+	CreatedAt   string              `json:"created_at"`
+	UpdatedAt   string              `json:"updated_at"`
+}
+
+// SecurityGroupOpts is used to create and update security groups, nil and
+// empty fields are not sent.
+type SecurityGroupOpts struct {
+	Name        *string `json:"name,omitempty"`
+	Description *string `json:"description,omitempty"`
+	TenantId    string  `json:"tenant_id,omitempty"`
+	Stateful    *bool   `json:"stateful,omitempty"`
+}
+
+// An empty Protocol matches any protocol and nil port ranges match any
+// port. For ICMP PortRangeMin and PortRangeMax hold the type and code.
+type SecurityGroupRule struct {
+	Id                   string `json:"id"`
+	SecurityGroupId      string `json:"security_group_id"`
+	Direction            string `json:"direction"`
+	EtherType            string `json:"ethertype"`
+	Protocol             string `json:"protocol"`
+	PortRangeMin         *int   `json:"port_range_min"`
+	PortRangeMax         *int   `json:"port_range_max"`
+	RemoteIPPrefix       string `json:"remote_ip_prefix"`
+	RemoteGroupId        string `json:"remote_group_id"`
+	RemoteAddressGroupId string `json:"remote_address_group_id"`
+	Description          string `json:"description"`
+	TenantId             string `json:"tenant_id"`
+}
+
+// SecurityGroupRuleOpts is used to create rules, rules can not be updated.
+// At most one of RemoteIPPrefix, RemoteGroupId and RemoteAddressGroupId
+// may be set.
+type SecurityGroupRuleOpts struct {
+	SecurityGroupId      string `json:"security_group_id,omitempty"`
+	Direction            string `json:"direction,omitempty"`
+	EtherType            string `json:"ethertype,omitempty"`
+	Protocol             string `json:"protocol,omitempty"`
+	PortRangeMin         *int   `json:"port_range_min,omitempty"`
+	PortRangeMax         *int   `json:"port_range_max,omitempty"`
+	RemoteIPPrefix       string `json:"remote_ip_prefix,omitempty"`
+	RemoteGroupId        string `json:"remote_group_id,omitempty"`
+	RemoteAddressGroupId string `json:"remote_address_group_id,omitempty"`
+	Description          string `json:"description,omitempty"`
+}
+
+func GetSecurityGroups(auth identity.Auth) (securityGroups []SecurityGroup, err error) {
+
+	return getSecurityGroups(auth, url.Values{})
+}
+
+// GetSecurityGroupsByName lists the security groups of the authenticated
+// tenant with the given name.
+func GetSecurityGroupsByName(auth identity.Auth, name string) (securityGroups []SecurityGroup, err error) {
+
+	query := url.Values{}
+	query.Set("name", name)
+	query.Set("tenant_id", auth.Access.Token.Tenant.Id)
+
+	return getSecurityGroups(auth, query)
+}
+
+func getSecurityGroups(auth identity.Auth, query url.Values) (securityGroups []SecurityGroup, err error) {
+
+	req := gorequest.New()
+
+	reqUrl := fmt.Sprintf("%s/v2.0/security-groups?%s",
+		auth.EndpointList["network"],
+		query.Encode())
+
+	resp, body, errs := req.Get(reqUrl).
+		Set("Content-Type", "application/json").
+		Set("Accept", "application/json").
+		Set("X-Auth-Token", auth.Access.Token.Id).
+		End()
+
+	if errs != nil {
+		err = errs[len(errs)-1]
+		return
+	}
+
+	if err = openstack.CheckHttpResponseStatusCode(resp.StatusCode); err != nil {
+		return
+	}
+
+	var r = securityGroupsResp{}
+	if err = json.Unmarshal([]byte(body), &r); err != nil {
+		return
+	}
+
+	securityGroups = r.SecurityGroups
+	err = nil
+	return
+}
+
+func GetSecurityGroup(auth identity.Auth, id string) (securityGroup SecurityGroup, err error) {
+
+	req := gorequest.New()
+
+	url := fmt.Sprintf("%s/v2.0/security-groups/%s",
+		auth.EndpointList["network"],
+		id)
+
+	resp, body, errs := req.Get(url).
+		Set("Content-Type", "application/json").
+		Set("Accept", "application/json").
+		Set("X-Auth-Token", auth.Access.Token.Id).
+		End()
+
+	if errs != nil {
+		err = errs[len(errs)-1]
+		return
+	}
+
+	if err = openstack.CheckHttpResponseStatusCode(resp.StatusCode); err != nil {
+		return
+	}
+
+	var r = securityGroupResp{}
+	if err = json.Unmarshal([]byte(body), &r); err != nil {
+		return
+	}
+
+	securityGroup = r.SecurityGroup
+	err = nil
+	return
+}
+
+// CreateSecurityGroup creates the group, Neutron adds default egress rules
+// to new groups.
+func CreateSecurityGroup(auth identity.Auth, opts SecurityGroupOpts) (securityGroup SecurityGroup, err error) {
+
+	req := gorequest.New()
+
+	url := fmt.Sprintf("%s/v2.0/security-groups",
+		auth.EndpointList["network"])
+
+	b, err := json.Marshal(securityGroupReq{opts})
+	if err != nil {
+		return
+	}
+
+	resp, body, errs := req.Post(url).
+		Set("Content-Type", "application/json").
+		Set("Accept", "application/json").
+		Set("X-Auth-Token", auth.Access.Token.Id).
+		Send(string(b)).
+		End()
+
+	if errs != nil {
+		err = errs[len(errs)-1]
+		return
+	}
+
+	if err = openstack.CheckHttpResponseStatusCode(resp.StatusCode); err != nil {
+		return
+	}
+
+	var r = securityGroupResp{}
+	if err = json.Unmarshal([]byte(body), &r); err != nil {
+		return
+	}
+
+	securityGroup = r.SecurityGroup
+	err = nil
+	return
+}
+
+func UpdateSecurityGroup(auth identity.Auth, id string, opts SecurityGroupOpts) (securityGroup SecurityGroup, err error) {
+
+	req := gorequest.New()
+
+	url := fmt.Sprintf("%s/v2.0/security-groups/%s",
+		auth.EndpointList["network"],
+		id)
+
+	b, err := json.Marshal(securityGroupReq{opts})
+	if err != nil {
+		return
+	}
+
+	resp, body, errs := req.Put(url).
+		Set("Content-Type", "application/json").
+		Set("Accept", "application/json").
+		Set("X-Auth-Token", auth.Access.Token.Id).
+		Send(string(b)).
+		End()
+
+	if errs != nil {
+		err = errs[len(errs)-1]
+		return
+	}
+
+	if err = openstack.CheckHttpResponseStatusCode(resp.StatusCode); err != nil {
+		return
+	}
+
+	var r = securityGroupResp{}
+	if err = json.Unmarshal([]byte(body), &r); err != nil {
+		return
+	}
+
+	securityGroup = r.SecurityGroup
+	err = nil
+	return
+}
+
+func DeleteSecurityGroup(auth identity.Auth, id string) (err error) {
+
+	req := gorequest.New()
+
+	url := fmt.Sprintf("%s/v2.0/security-groups/%s",
+		auth.EndpointList["network"],
+		id)
+
+	resp, _, errs := req.Delete(url).
+		Set("Content-Type", "application/json").
+		Set("Accept", "application/json").
+		Set("X-Auth-Token", auth.Access.Token.Id).
+		End()
+
+	if errs != nil {
+		err = errs[len(errs)-1]
+		return
+	}
+
+	if err = openstack.CheckHttpResponseStatusCode(resp.StatusCode); err != nil {
+		return
+	}
+
+	err = nil
+	return
+}
+
+func GetSecurityGroupRules(auth identity.Auth, securityGroupId string) (rules []SecurityGroupRule, err error) {
+
+	req := gorequest.New()
+
+	query := url.Values{}
+	query.Set("security_group_id", securityGroupId)
+
+	reqUrl := fmt.Sprintf("%s/v2.0/security-group-rules?%s",
+		auth.EndpointList["network"],
+		query.Encode())
+
+	resp, body, errs := req.Get(reqUrl).
+		Set("Content-Type", "application/json").
+		Set("Accept", "application/json").
+		Set("X-Auth-Token", auth.Access.Token.Id).
+		End()
+
+	if errs != nil {
+		err = errs[len(errs)-1]
+		return
+	}
+
+	if err = openstack.CheckHttpResponseStatusCode(resp.StatusCode); err != nil {
+		return
+	}
+
+	var r = securityGroupRulesResp{}
+	if err = json.Unmarshal([]byte(body), &r); err != nil {
+		return
+	}
+
+	rules = r.SecurityGroupRules
+	err = nil
+	return
+}
+
+func GetSecurityGroupRule(auth identity.Auth, id string) (rule SecurityGroupRule, err error) {
+
+	req := gorequest.New()
+
+	url := fmt.Sprintf("%s/v2.0/security-group-rules/%s",
+		auth.EndpointList["network"],
+		id)
+
+	resp, body, errs := req.Get(url).
+		Set("Content-Type", "application/json").
+		Set("Accept", "application/json").
+		Set("X-Auth-Token", auth.Access.Token.Id).
+		End()
+
+	if errs != nil {
+		err = errs[len(errs)-1]
+		return
+	}
+
+	if err = openstack.CheckHttpResponseStatusCode(resp.StatusCode); err != nil {
+		return
+	}
+
+	var r = securityGroupRuleResp{}
+	if err = json.Unmarshal([]byte(body), &r); err != nil {
+		return
+	}
+
+	rule = r.SecurityGroupRule
+	err = nil
+	return
+}
+
+func CreateSecurityGroupRule(auth identity.Auth, opts SecurityGroupRuleOpts) (rule SecurityGroupRule, err error) {
+
+	req := gorequest.New()
+
+	url := fmt.Sprintf("%s/v2.0/security-group-rules",
+		auth.EndpointList["network"])
+
+	b, err := json.Marshal(securityGroupRuleReq{opts})
+	if err != nil {
+		return
+	}
+
+	resp, body, errs := req.Post(url).
+		Set("Content-Type", "application/json").
+		Set("Accept", "application/json").
+		Set("X-Auth-Token", auth.Access.Token.Id).
+		Send(string(b)).
+		End()
+
+	if errs != nil {
+		err = errs[len(errs)-1]
+		return
+	}
+
+	if err = openstack.CheckHttpResponseStatusCode(resp.StatusCode); err != nil {
+		return
+	}
+
+	var r = securityGroupRuleResp{}
+	if err = json.Unmarshal([]byte(body), &r); err != nil {
+		return
+	}
+
+	rule = r.SecurityGroupRule
+	err = nil
+	return
+}
+
+func DeleteSecurityGroupRule(auth identity.Auth, id string) (err error) {
+
+	req := gorequest.New()
+
+	url := fmt.Sprintf("%s/v2.0/security-group-rules/%s",
+		auth.EndpointList["network"],
+		id)
+
+	resp, _, errs := req.Delete(url).
+		Set("Content-Type", "application/json").
+		Set("Accept", "application/json").
+		Set("X-Auth-Token", auth.Access.Token.Id).
+		End()
+
+	if errs != nil {
+		err = errs[len(errs)-1]
+		return
+	}
+
+	if err = openstack.CheckHttpResponseStatusCode(resp.StatusCode); err != nil {
+		return
+	}
+
+	err = nil
+	return
+}
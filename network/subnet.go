@@ -4,6 +4,7 @@ import (
 	"encoding/json"
 	"fmt"
 
+	"github.com/gertd/go-openstack"
 	"github.com/gertd/go-openstack/identity"
 	"github.com/parnurzeal/gorequest"
 )
@@ -12,18 +13,37 @@ type subnetsResp struct {
 	Subnets []Subnet `json:"subnets,omitempty"`
 }
 
+type subnetResp struct {
+	Subnet Subnet `json:"subnet"`
+}
+
+type subnetReq struct {
+	Subnet SubnetOpts `json:"subnet"`
+}
+
+// GatewayIP is empty when the subnet has no gateway.
 type Subnet struct {
-	Id         string `json:"id,omitempty"`
-	Name       string `json:"name,omitempty"`
-	NetworkId  string `json:"network_id,omitempty"`
-	TenantId   string `json:"tenant_id,omitempty"`
-	EnableDHCP bool   `json:"enable_dhcp,omitempty"`
-	//DnsNameserver   []string         `json:"dns_nameservers,omitempty"`
-	//AllocationPools []AllocationPool `json:"allocation_pools,omitempty"`
-	//HostRoutes      []string         `json:"host_routes,omitempty"`
-	IPVersion int    `json:"ip_version,omitempty"`
-	GatewayIP string `json:"gateway_ip,omitempty"`
-	CIDR      string `json:"cidr,omitempty"`
+	Id              string           `json:"id"`
+	Name            string           `json:"name"`
+	Description     string           `json:"description"`
+	NetworkId       string           `json:"network_id"`
+	TenantId        string           `json:"tenant_id"`
+	ProjectId       string           `json:"project_id"`
+	EnableDHCP      bool             `json:"enable_dhcp"`
+	DnsNameservers  []string         `json:"dns_nameservers"`
+	AllocationPools []AllocationPool `json:"allocation_pools"`
+	HostRoutes      []HostRoute      `json:"host_routes"`
+	IPVersion       int              `json:"ip_version"`
+	GatewayIP       string           `json:"gateway_ip"`
+	CIDR            string           `json:"cidr"`
+	IPv6RAMode      string           `json:"ipv6_ra_mode"`
+	IPv6AddressMode string           `json:"ipv6_address_mode"`
+	SubnetPoolId    string           `json:"subnetpool_id"`
+	SegmentId       string           `json:"segment_id"`
+	ServiceTypes    []string         `json:"service_types"`
+	Tags            []string         `json:"tags"`
+	CreatedAt       string           `json:"created_at"`
+	UpdatedAt       string           `json:"updated_at"`
 }
 
 type AllocationPool struct {
@@ -31,6 +51,55 @@ type AllocationPool struct {
 	End   string `json:"end,omitempty"`
 }
 
+type HostRoute struct {
+	Destination string `json:"destination"`
+	NextHop     string `json:"nexthop"`
+}
+
+// SubnetOpts is used to create and update subnets, nil and empty fields
+// are not sent. The slices are pointers so that a list can be cleared by
+// pointing to an empty slice. A GatewayIP pointing to an empty string
+// disables the gateway. PrefixLen is used instead of CIDR to allocate from
+// SubnetPoolId.
+type SubnetOpts struct {
+	Name            *string           `json:"name,omitempty"`
+	Description     *string           `json:"description,omitempty"`
+	NetworkId       string            `json:"network_id,omitempty"`
+	TenantId        string            `json:"tenant_id,omitempty"`
+	IPVersion       int               `json:"ip_version,omitempty"`
+	CIDR            string            `json:"cidr,omitempty"`
+	GatewayIP       *string           `json:"gateway_ip,omitempty"`
+	EnableDHCP      *bool             `json:"enable_dhcp,omitempty"`
+	DnsNameservers  *[]string         `json:"dns_nameservers,omitempty"`
+	AllocationPools *[]AllocationPool `json:"allocation_pools,omitempty"`
+	HostRoutes      *[]HostRoute      `json:"host_routes,omitempty"`
+	IPv6RAMode      string            `json:"ipv6_ra_mode,omitempty"`
+	IPv6AddressMode string            `json:"ipv6_address_mode,omitempty"`
+	SubnetPoolId    string            `json:"subnetpool_id,omitempty"`
+	PrefixLen       int               `json:"prefixlen,omitempty"`
+	SegmentId       string            `json:"segment_id,omitempty"`
+	ServiceTypes    *[]string         `json:"service_types,omitempty"`
+}
+
+func (opts SubnetOpts) MarshalJSON() (b []byte, err error) {
+
+	type subnetOpts SubnetOpts
+
+	b, err = json.Marshal(subnetOpts(opts))
+	if err != nil || opts.GatewayIP == nil || len(*opts.GatewayIP) > 0 {
+		return
+	}
+
+	var attrs map[string]interface{}
+	if err = json.Unmarshal(b, &attrs); err != nil {
+		return
+	}
+
+	attrs["gateway_ip"] = nil
+
+	return json.Marshal(attrs)
+}
+
 func GetSubnets(auth identity.Auth) (subnets []Subnet, err error) {
 
 	req := gorequest.New()
@@ -38,7 +107,7 @@ func GetSubnets(auth identity.Auth) (subnets []Subnet, err error) {
 	url := fmt.Sprintf("%s/v2.0/subnets",
 		auth.EndpointList["network"])
 
-	_, body, errs := req.Get(url).
+	resp, body, errs := req.Get(url).
 		Set("Content-Type", "application/json").
 		Set("Accept", "application/json").
 		Set("X-Auth-Token", auth.Access.Token.Id).
@@ -49,9 +118,12 @@ func GetSubnets(auth identity.Auth) (subnets []Subnet, err error) {
 		return
 	}
 
+	if err = openstack.CheckHttpResponseStatusCode(resp.StatusCode); err != nil {
+		return
+	}
+
 	var sn = subnetsResp{}
 	if err = json.Unmarshal([]byte(body), &sn); err != nil {
-		fmt.Println(err)
 		return
 	}
 
@@ -59,3 +131,140 @@ func GetSubnets(auth identity.Auth) (subnets []Subnet, err error) {
 	err = nil
 	return
 }
+
+func GetSubnet(auth identity.Auth, id string) (subnet Subnet, err error) {
+
+	req := gorequest.New()
+
+	url := fmt.Sprintf("%s/v2.0/subnets/%s",
+		auth.EndpointList["network"],
+		id)
+
+	resp, body, errs := req.Get(url).
+		Set("Content-Type", "application/json").
+		Set("Accept", "application/json").
+		Set("X-Auth-Token", auth.Access.Token.Id).
+		End()
+
+	if errs != nil {
+		err = errs[len(errs)-1]
+		return
+	}
+
+	if err = openstack.CheckHttpResponseStatusCode(resp.StatusCode); err != nil {
+		return
+	}
+
+	var r = subnetResp{}
+	if err = json.Unmarshal([]byte(body), &r); err != nil {
+		return
+	}
+
+	subnet = r.Subnet
+	err = nil
+	return
+}
+
+func CreateSubnet(auth identity.Auth, opts SubnetOpts) (subnet Subnet, err error) {
+
+	req := gorequest.New()
+
+	url := fmt.Sprintf("%s/v2.0/subnets",
+		auth.EndpointList["network"])
+
+	b, err := json.Marshal(subnetReq{opts})
+	if err != nil {
+		return
+	}
+
+	resp, body, errs := req.Post(url).
+		Set("Content-Type", "application/json").
+		Set("Accept", "application/json").
+		Set("X-Auth-Token", auth.Access.Token.Id).
+		Send(string(b)).
+		End()
+
+	if errs != nil {
+		err = errs[len(errs)-1]
+		return
+	}
+
+	if err = openstack.CheckHttpResponseStatusCode(resp.StatusCode); err != nil {
+		return
+	}
+
+	var r = subnetResp{}
+	if err = json.Unmarshal([]byte(body), &r); err != nil {
+		return
+	}
+
+	subnet = r.Subnet
+	err = nil
+	return
+}
+
+func UpdateSubnet(auth identity.Auth, id string, opts SubnetOpts) (subnet Subnet, err error) {
+
+	req := gorequest.New()
+
+	url := fmt.Sprintf("%s/v2.0/subnets/%s",
+		auth.EndpointList["network"],
+		id)
+
+	b, err := json.Marshal(subnetReq{opts})
+	if err != nil {
+		return
+	}
+
+	resp, body, errs := req.Put(url).
+		Set("Content-Type", "application/json").
+		Set("Accept", "application/json").
+		Set("X-Auth-Token", auth.Access.Token.Id).
+		Send(string(b)).
+		End()
+
+	if errs != nil {
+		err = errs[len(errs)-1]
+		return
+	}
+
+	if err = openstack.CheckHttpResponseStatusCode(resp.StatusCode); err != nil {
+		return
+	}
+
+	var r = subnetResp{}
+	if err = json.Unmarshal([]byte(body), &r); err != nil {
+		return
+	}
+
+	subnet = r.Subnet
+	err = nil
+	return
+}
+
+func DeleteSubnet(auth identity.Auth, id string) (err error) {
+
+	req := gorequest.New()
+
+	url := fmt.Sprintf("%s/v2.0/subnets/%s",
+		auth.EndpointList["network"],
+		id)
+
+	resp, _, errs := req.Delete(url).
+		Set("Content-Type", "application/json").
+		Set("Accept", "application/json").
+		Set("X-Auth-Token", auth.Access.Token.Id).
+		End()
+
+	if errs != nil {
+		err = errs[len(errs)-1]
+		return
+	}
+
+	if err = openstack.CheckHttpResponseStatusCode(resp.StatusCode); err != nil {
+		return
+	}
+
+	err = nil
+	return
+}
@@ -0,0 +1,309 @@
+package network
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/gertd/go-openstack"
+	"github.com/gertd/go-openstack/identity"
+	"github.com/parnurzeal/gorequest"
+)
+
+type subnetPoolsResp struct {
+	SubnetPools []SubnetPool `json:"subnetpools"`
+}
+
+type subnetPoolResp struct {
+	SubnetPool SubnetPool `json:"subnetpool"`
+}
+
+type subnetPoolReq struct {
+	SubnetPool SubnetPoolOpts `json:"subnetpool"`
+}
+
+type subnetPoolPrefixesReq struct {
+	Prefixes []string `json:"prefixes"`
+}
+
+type SubnetPool struct {
+	Id               string   `json:"id"`
+	Name             string   `json:"name"`
+	Description      string   `json:"description"`
+	Prefixes         []string `json:"prefixes"`
+	DefaultPrefixLen int      `json:"default_prefixlen"`
+	MinPrefixLen     int      `json:"min_prefixlen"`
+	MaxPrefixLen     int      `json:"max_prefixlen"`
+	DefaultQuota     int      `json:"default_quota"`
+	AddressScopeId   string   `json:"address_scope_id"`
+	IPVersion        int      `json:"ip_version"`
+	Shared           bool     `json:"shared"`
+	IsDefault        bool     `json:"is_default"`
+	TenantId         string   `json:"tenant_id"`
+	ProjectId        string   `json:"project_id"`
+	Tags             []string `json:"tags"`
+	CreatedAt        string   `json:"created_at"`
+	UpdatedAt        string   `json:"updated_at"`
+}
+
+// SubnetPoolOpts is used to create and update subnet pools, nil and empty
+// fields are not sent. An AddressScopeId pointing to an empty string
+// removes the pool from its address scope. Prefixes can only grow.
+type SubnetPoolOpts struct {
+	Name             *string   `json:"name,omitempty"`
+	Description      *string   `json:"description,omitempty"`
+	TenantId         string    `json:"tenant_id,omitempty"`
+	Prefixes         *[]string `json:"prefixes,omitempty"`
+	DefaultPrefixLen *int      `json:"default_prefixlen,omitempty"`
+	MinPrefixLen     *int      `json:"min_prefixlen,omitempty"`
+	MaxPrefixLen     *int      `json:"max_prefixlen,omitempty"`
+	DefaultQuota     *int      `json:"default_quota,omitempty"`
+	AddressScopeId   *string   `json:"address_scope_id,omitempty"`
+	Shared           *bool     `json:"shared,omitempty"`
+	IsDefault        *bool     `json:"is_default,omitempty"`
+}
+
+func (opts SubnetPoolOpts) MarshalJSON() (b []byte, err error) {
+
+	type subnetPoolOpts SubnetPoolOpts
+
+	b, err = json.Marshal(subnetPoolOpts(opts))
+	if err != nil || opts.AddressScopeId == nil || len(*opts.AddressScopeId) > 0 {
+		return
+	}
+
+	var attrs map[string]interface{}
+	if err = json.Unmarshal(b, &attrs); err != nil {
+		return
+	}
+
+	attrs["address_scope_id"] = nil
+
+	return json.Marshal(attrs)
+}
+
+func GetSubnetPools(auth identity.Auth) (subnetPools []SubnetPool, err error) {
+
+	req := gorequest.New()
+
+	url := fmt.Sprintf("%s/v2.0/subnetpools",
+		auth.EndpointList["network"])
+
+	resp, body, errs := req.Get(url).
+		Set("Content-Type", "application/json").
+		Set("Accept", "application/json").
+		Set("X-Auth-Token", auth.Access.Token.Id).
+		End()
+
+	if errs != nil {
+		err = errs[len(errs)-1]
+		return
+	}
+
+	if err = openstack.CheckHttpResponseStatusCode(resp.StatusCode); err != nil {
+		return
+	}
+
+	var r = subnetPoolsResp{}
+	if err = json.Unmarshal([]byte(body), &r); err != nil {
+		return
+	}
+
+	subnetPools = r.SubnetPools
+	err = nil
+	return
+}
+
+func GetSubnetPool(auth identity.Auth, id string) (subnetPool SubnetPool, err error) {
+
+	req := gorequest.New()
+
+	url := fmt.Sprintf("%s/v2.0/subnetpools/%s",
+		auth.EndpointList["network"],
+		id)
+
+	resp, body, errs := req.Get(url).
+		Set("Content-Type", "application/json").
+		Set("Accept", "application/json").
+		Set("X-Auth-Token", auth.Access.Token.Id).
+		End()
+
+	if errs != nil {
+		err = errs[len(errs)-1]
+		return
+	}
+
+	if err = openstack.CheckHttpResponseStatusCode(resp.StatusCode); err != nil {
+		return
+	}
+
+	var r = subnetPoolResp{}
+	if err = json.Unmarshal([]byte(body), &r); err != nil {
+		return
+	}
+
+	subnetPool = r.SubnetPool
+	err = nil
+	return
+}
+
+func CreateSubnetPool(auth identity.Auth, opts SubnetPoolOpts) (subnetPool SubnetPool, err error) {
+
+	req := gorequest.New()
+
+	url := fmt.Sprintf("%s/v2.0/subnetpools",
+		auth.EndpointList["network"])
+
+	b, err := json.Marshal(subnetPoolReq{opts})
+	if err != nil {
+		return
+	}
+
+	resp, body, errs := req.Post(url).
+		Set("Content-Type", "application/json").
+		Set("Accept", "application/json").
+		Set("X-Auth-Token", auth.Access.Token.Id).
+		Send(string(b)).
+		End()
+
+	if errs != nil {
+		err = errs[len(errs)-1]
+		return
+	}
+
+	if err = openstack.CheckHttpResponseStatusCode(resp.StatusCode); err != nil {
+		return
+	}
+
+	var r = subnetPoolResp{}
+	if err = json.Unmarshal([]byte(body), &r); err != nil {
+		return
+	}
+
+	subnetPool = r.SubnetPool
+	err = nil
+	return
+}
+
+func UpdateSubnetPool(auth identity.Auth, id string, opts SubnetPoolOpts) (subnetPool SubnetPool, err error) {
+
+	req := gorequest.New()
+
+	url := fmt.Sprintf("%s/v2.0/subnetpools/%s",
+		auth.EndpointList["network"],
+		id)
+
+	b, err := json.Marshal(subnetPoolReq{opts})
+	if err != nil {
+		return
+	}
+
+	resp, body, errs := req.Put(url).
+		Set("Content-Type", "application/json").
+		Set("Accept", "application/json").
+		Set("X-Auth-Token", auth.Access.Token.Id).
+		Send(string(b)).
+		End()
+
+	if errs != nil {
+		err = errs[len(errs)-1]
+		return
+	}
+
+	if err = openstack.CheckHttpResponseStatusCode(resp.StatusCode); err != nil {
+		return
+	}
+
+	var r = subnetPoolResp{}
+	if err = json.Unmarshal([]byte(body), &r); err != nil {
+		return
+	}
+
+	subnetPool = r.SubnetPool
+	err = nil
+	return
+}
+
+func DeleteSubnetPool(auth identity.Auth, id string) (err error) {
+
+	req := gorequest.New()
+
+	url := fmt.Sprintf("%s/v2.0/subnetpools/%s",
+		auth.EndpointList["network"],
+		id)
+
+	resp, _, errs := req.Delete(url).
+		Set("Content-Type", "application/json").
+		Set("Accept", "application/json").
+		Set("X-Auth-Token", auth.Access.Token.Id).
+		End()
+
+	if errs != nil {
+		err = errs[len(errs)-1]
+		return
+	}
+
+	if err = openstack.CheckHttpResponseStatusCode(resp.StatusCode); err != nil {
+		return
+	}
+
+	err = nil
+	return
+}
+
+// AddSubnetPoolPrefixes adds prefixes to the pool, overlapping prefixes are
+// merged by Neutron.
+func AddSubnetPoolPrefixes(auth identity.Auth, id string, prefixes []string) (err error) {
+
+	req := gorequest.New()
+
+	url := fmt.Sprintf("%s/v2.0/subnetpools/%s/add_prefixes",
+		auth.EndpointList["network"],
+		id)
+
+	b, err := json.Marshal(subnetPoolPrefixesReq{prefixes})
+	if err != nil {
+		return
+	}
+
+	resp, _, errs := req.Put(url).
+		Set("Content-Type", "application/json").
+		Set("Accept", "application/json").
+		Set("X-Auth-Token", auth.Access.Token.Id).
+		Send(string(b)).
+		End()
+
+	if errs != nil {
+		err = errs[len(errs)-1]
+		return
+	}
+
+	if err = openstack.CheckHttpResponseStatusCode(resp.StatusCode); err != nil {
+		return
+	}
+
+	err = nil
+	return
+}
+
+// AllocateSubnetFromPool creates a subnet on the network with the next free
+// prefix of the given length from the pool, a prefixLen of zero uses the
+// default prefix length of the pool.
+func AllocateSubnetFromPool(auth identity.Auth, poolId string, networkId string, prefixLen int, name string) (subnet Subnet, err error) {
+
+	pool, err := GetSubnetPool(auth, poolId)
+	if err != nil {
+		return
+	}
+
+	opts := SubnetOpts{
+		NetworkId:    networkId,
+		IPVersion:    pool.IPVersion,
+		SubnetPoolId: poolId,
+		PrefixLen:    prefixLen,
+	}
+	if len(name) > 0 {
+		opts.Name = &name
+	}
+
+	return CreateSubnet(auth, opts)
+}
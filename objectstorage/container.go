@@ -0,0 +1,183 @@
+package objectstorage
+
+import (
+	"github.com/gertd/go-openstack"
+	"github.com/gertd/go-openstack/identity"
+
+	"github.com/parnurzeal/gorequest"
+)
+
+// Container ACLs. Read ACLs take referrer rules, ACLPublicRead and
+// ACLListing combined make the container browsable anonymously. Project and
+// user grants are written project:user, with * as wildcard.
+const (
+	ACLPublicRead = ".r:*"
+	ACLListing    = ".rlistings"
+)
+
+type Container struct {
+	Name         string `json:"name"`
+	Count        int64  `json:"count"`
+	Bytes        int64  `json:"bytes"`
+	LastModified string `json:"last_modified"`
+}
+
+type ContainerInfo struct {
+	ObjectCount   int64
+	BytesUsed     int64
+	ReadACL       string
+	WriteACL      string
+	StoragePolicy string
+	Metadata      map[string]string
+}
+
+// ContainerOpts is used to create and update containers, nil and empty
+// fields are not sent. A pointer to an empty ACL removes it. StoragePolicy
+// can only be set on creation. Metadata keys not in Metadata are kept
+// unless listed in RemoveMetadata.
+type ContainerOpts struct {
+	ReadACL        *string
+	WriteACL       *string
+	StoragePolicy  string
+	Metadata       map[string]string
+	RemoveMetadata []string
+}
+
+func (opts ContainerOpts) headers() (headers map[string]string) {
+
+	headers = make(map[string]string)
+
+	if opts.ReadACL != nil {
+		if len(*opts.ReadACL) > 0 {
+			headers["X-Container-Read"] = *opts.ReadACL
+		} else {
+			headers["X-Remove-Container-Read"] = "x"
+		}
+	}
+	if opts.WriteACL != nil {
+		if len(*opts.WriteACL) > 0 {
+			headers["X-Container-Write"] = *opts.WriteACL
+		} else {
+			headers["X-Remove-Container-Write"] = "x"
+		}
+	}
+	if len(opts.StoragePolicy) > 0 {
+		headers["X-Storage-Policy"] = opts.StoragePolicy
+	}
+
+	setMetadataHeaders(headers, containerMetaPrefix, opts.Metadata, opts.RemoveMetadata)
+
+	return headers
+}
+
+func GetContainers(auth identity.Auth, opts ListOpts) (containers []Container, err error) {
+
+	marker := opts.Marker
+
+	for {
+		var page []Container
+		if err = getListing(auth, auth.EndpointList["object-store"], opts, marker, &page); err != nil {
+			return
+		}
+
+		containers = append(containers, page...)
+
+		if opts.Limit > 0 || len(page) == 0 {
+			break
+		}
+		marker = page[len(page)-1].Name
+	}
+
+	err = nil
+	return
+}
+
+func GetContainer(auth identity.Auth, name string) (container ContainerInfo, err error) {
+
+	req := gorequest.New()
+
+	resp, _, errs := req.Head(containerURL(auth, name)).
+		Set("X-Auth-Token", auth.Access.Token.Id).
+		End()
+
+	if errs != nil {
+		err = errs[len(errs)-1]
+		return
+	}
+
+	if err = openstack.CheckHttpResponseStatusCode(resp.StatusCode); err != nil {
+		return
+	}
+
+	container = ContainerInfo{
+		ObjectCount:   headerInt(resp.Header, "X-Container-Object-Count"),
+		BytesUsed:     headerInt(resp.Header, "X-Container-Bytes-Used"),
+		ReadACL:       resp.Header.Get("X-Container-Read"),
+		WriteACL:      resp.Header.Get("X-Container-Write"),
+		StoragePolicy: resp.Header.Get("X-Storage-Policy"),
+		Metadata:      headerMetadata(resp.Header, containerMetaPrefix),
+	}
+	err = nil
+	return
+}
+
+// CreateContainer creates the container, or updates it when it exists.
+func CreateContainer(auth identity.Auth, name string, opts ContainerOpts) (err error) {
+
+	req := gorequest.New()
+
+	req.Put(containerURL(auth, name)).
+		Set("X-Auth-Token", auth.Access.Token.Id)
+
+	for k, v := range opts.headers() {
+		req.Set(k, v)
+	}
+
+	resp, _, errs := req.End()
+
+	if errs != nil {
+		err = errs[len(errs)-1]
+		return
+	}
+
+	if err = openstack.CheckHttpResponseStatusCode(resp.StatusCode); err != nil {
+		return
+	}
+
+	err = nil
+	return
+}
+
+func UpdateContainer(auth identity.Auth, name string, opts ContainerOpts) (err error) {
+
+	return sendHeaders(auth, "POST", containerURL(auth, name), opts.headers())
+}
+
+// SetContainerACL replaces the read and write ACLs of the container, an
+// empty ACL removes it.
+func SetContainerACL(auth identity.Auth, name string, readACL string, writeACL string) (err error) {
+
+	return UpdateContainer(auth, name, ContainerOpts{ReadACL: &readACL, WriteACL: &writeACL})
+}
+
+// DeleteContainer deletes the container, it must be empty.
+func DeleteContainer(auth identity.Auth, name string) (err error) {
+
+	req := gorequest.New()
+
+	resp, _, errs := req.Delete(containerURL(auth, name)).
+		Set("X-Auth-Token", auth.Access.Token.Id).
+		End()
+
+	if errs != nil {
+		err = errs[len(errs)-1]
+		return
+	}
+
+	if err = openstack.CheckHttpResponseStatusCode(resp.StatusCode); err != nil {
+		return
+	}
+
+	err = nil
+	return
+}
@@ -0,0 +1,499 @@
+package objectstorage
+
+import (
+	"bytes"
+	"crypto/md5"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gertd/go-openstack"
+	"github.com/gertd/go-openstack/identity"
+
+	"github.com/parnurzeal/gorequest"
+)
+
+const (
+	defaultSegmentSize = 100 * 1024 * 1024
+	defaultConcurrency = 4
+	defaultRetries     = 3
+	retryInterval      = 2 * time.Second
+
+	// maxSLOSegments is the default max_manifest_segments of Swift
+	maxSLOSegments = 1000
+)
+
+type sloSegment struct {
+	Path      string `json:"path"`
+	ETag      string `json:"etag"`
+	SizeBytes int64  `json:"size_bytes"`
+}
+
+type sloManifestEntry struct {
+	Name   string `json:"name"`
+	Hash   string `json:"hash"`
+	Bytes  int64  `json:"bytes"`
+	SubSLO bool   `json:"sub_slo"`
+	Range  string `json:"range"`
+}
+
+// LargeObjectOpts controls UploadLargeObject, zero fields take their
+// default. Segments are SegmentSize bytes (100 MiB) and are stored in
+// SegmentContainer (the container name suffixed with _segments). Up to
+// Concurrency segments (4) are held in memory and uploaded at once, each is
+// retried Retries times (3). Dynamic writes a DLO manifest instead of an SLO
+// one. ObjectOpts apply to the manifest.
+type LargeObjectOpts struct {
+	SegmentSize      int64
+	SegmentContainer string
+	Concurrency      int
+	Retries          int
+	Dynamic          bool
+	ObjectOpts       ObjectOpts
+}
+
+type bulkDeleteResp struct {
+	ResponseStatus string     `json:"Response Status"`
+	ResponseBody   string     `json:"Response Body"`
+	Errors         [][]string `json:"Errors"`
+}
+
+type LargeObjectResult struct {
+	Segments int
+	Uploaded int
+	Skipped  int
+	Bytes    int64
+}
+
+// UploadLargeObject splits r into segments, uploads them and writes the
+// manifest as container/name. Segments are named after the object and the
+// segment size, an interrupted upload is resumed by calling it again with
+// the same name, segment size and data: segments already stored with a
+// matching ETag are not uploaded again. By default Swift accepts at most
+// 1000 segments in an SLO manifest, an SLO upload needing more fails before
+// anything is uploaded when r is an io.Seeker, or before the 1001st segment
+// otherwise.
+func UploadLargeObject(auth identity.Auth, container string, name string, r io.Reader, opts LargeObjectOpts) (result LargeObjectResult, err error) {
+
+	segmentSize := opts.SegmentSize
+	if segmentSize <= 0 {
+		segmentSize = defaultSegmentSize
+	}
+	segmentContainer := opts.SegmentContainer
+	if len(segmentContainer) == 0 {
+		segmentContainer = container + "_segments"
+	}
+	concurrency := opts.Concurrency
+	if concurrency <= 0 {
+		concurrency = defaultConcurrency
+	}
+	retries := opts.Retries
+	if retries <= 0 {
+		retries = defaultRetries
+	}
+
+	prefix := fmt.Sprintf("%s/%d/", name, segmentSize)
+
+	if s, ok := r.(io.Seeker); ok && !opts.Dynamic {
+		var count int64
+		if count, err = remainingSegments(s, segmentSize); err != nil {
+			return
+		}
+		if count > maxSLOSegments {
+			err = errors.New(fmt.Sprintf("object %s/%s needs %d segments of %d bytes, an SLO manifest holds at most %d", container, name, count, segmentSize, maxSLOSegments))
+			return
+		}
+	}
+
+	if err = CreateContainer(auth, segmentContainer, ContainerOpts{}); err != nil {
+		return
+	}
+
+	existing, err := GetObjects(auth, segmentContainer, ListOpts{Prefix: prefix})
+	if err != nil {
+		return
+	}
+
+	stored := make(map[string]string)
+	for _, v := range existing {
+		stored[v.Name] = v.Hash
+	}
+
+	var (
+		segments []sloSegment
+		wg       sync.WaitGroup
+		mu       sync.Mutex
+		firstErr error
+	)
+
+	sem := make(chan struct{}, concurrency)
+
+	for index := 0; ; index++ {
+		sem <- struct{}{}
+
+		mu.Lock()
+		failed := firstErr != nil
+		mu.Unlock()
+		if failed {
+			<-sem
+			break
+		}
+
+		data := make([]byte, segmentSize)
+		n, e := io.ReadFull(r, data)
+		if e == io.EOF {
+			<-sem
+			break
+		}
+		if e != nil && e != io.ErrUnexpectedEOF {
+			<-sem
+			mu.Lock()
+			firstErr = e
+			mu.Unlock()
+			break
+		}
+		data = data[:n]
+
+		if index >= maxSLOSegments && !opts.Dynamic {
+			<-sem
+			mu.Lock()
+			firstErr = errors.New(fmt.Sprintf("object %s/%s needs more than %d segments of %d bytes, the most an SLO manifest holds", container, name, maxSLOSegments, segmentSize))
+			mu.Unlock()
+			break
+		}
+
+		segmentName := fmt.Sprintf("%s%08d", prefix, index)
+
+		sum := md5.Sum(data)
+		segment := sloSegment{
+			Path:      "/" + segmentContainer + "/" + segmentName,
+			ETag:      hex.EncodeToString(sum[:]),
+			SizeBytes: int64(n),
+		}
+
+		segments = append(segments, segment)
+		result.Bytes += int64(n)
+
+		if stored[segmentName] == segment.ETag {
+			result.Skipped++
+			<-sem
+		} else {
+			result.Uploaded++
+			wg.Add(1)
+			go func(segmentName string, data []byte, etag string) {
+				defer wg.Done()
+				defer func() { <-sem }()
+
+				if e := uploadSegment(auth, segmentContainer, segmentName, data, etag, retries); e != nil {
+					mu.Lock()
+					if firstErr == nil {
+						firstErr = e
+					}
+					mu.Unlock()
+				}
+			}(segmentName, data, segment.ETag)
+		}
+
+		if int64(n) < segmentSize {
+			break
+		}
+	}
+
+	wg.Wait()
+
+	if firstErr != nil {
+		err = firstErr
+		return
+	}
+
+	result.Segments = len(segments)
+
+	switch {
+	case len(segments) == 0:
+		// Swift refuses empty segments, an empty object needs no manifest
+		_, err = UploadObject(auth, container, name, bytes.NewReader(nil), 0, opts.ObjectOpts)
+	case opts.Dynamic:
+		err = putDLOManifest(auth, container, name, segmentContainer+"/"+prefix, opts.ObjectOpts)
+	default:
+		err = putSLOManifest(auth, container, name, segments, opts.ObjectOpts)
+	}
+	if err != nil {
+		return
+	}
+
+	// segments left over by an earlier upload of a longer object would end
+	// up in a DLO and waste space otherwise. They are deleted once the
+	// manifest is written so a failed upload leaves the old object readable
+	for _, v := range existing {
+		var index int
+		if _, e := fmt.Sscanf(strings.TrimPrefix(v.Name, prefix), "%08d", &index); e == nil && index >= len(segments) {
+			if err = DeleteObject(auth, segmentContainer, v.Name); err != nil {
+				return
+			}
+		}
+	}
+
+	err = nil
+	return
+}
+
+// remainingSegments counts the segments between the offset of s and its end,
+// leaving the offset unchanged.
+func remainingSegments(s io.Seeker, segmentSize int64) (count int64, err error) {
+
+	offset, err := s.Seek(0, io.SeekCurrent)
+	if err != nil {
+		return
+	}
+
+	end, err := s.Seek(0, io.SeekEnd)
+	if err != nil {
+		return
+	}
+
+	if _, err = s.Seek(offset, io.SeekStart); err != nil {
+		return
+	}
+
+	count = (end - offset + segmentSize - 1) / segmentSize
+
+	err = nil
+	return
+}
+
+func uploadSegment(auth identity.Auth, container string, name string, data []byte, etag string, retries int) (err error) {
+
+	for attempt := 0; ; attempt++ {
+		_, err = UploadObject(auth, container, name, bytes.NewReader(data), int64(len(data)), ObjectOpts{ETag: etag})
+		if err == nil || attempt >= retries {
+			return
+		}
+		time.Sleep(time.Duration(attempt+1) * retryInterval)
+	}
+}
+
+func putSLOManifest(auth identity.Auth, container string, name string, segments []sloSegment, opts ObjectOpts) (err error) {
+
+	req := gorequest.New()
+
+	reqUrl := fmt.Sprintf("%s?multipart-manifest=put",
+		objectURL(auth, container, name))
+
+	b, err := json.Marshal(segments)
+	if err != nil {
+		return
+	}
+
+	req.Put(reqUrl).
+		Set("Content-Type", "application/octet-stream").
+		Set("X-Auth-Token", auth.Access.Token.Id)
+
+	// the ETag of a manifest is checked against the segments, not the body
+	opts.ETag = ""
+	for k, v := range opts.headers() {
+		req.Set(k, v)
+	}
+
+	resp, _, errs := req.Send(string(b)).End()
+
+	if errs != nil {
+		err = errs[len(errs)-1]
+		return
+	}
+
+	if err = openstack.CheckHttpResponseStatusCode(resp.StatusCode); err != nil {
+		return
+	}
+
+	err = nil
+	return
+}
+
+func putDLOManifest(auth identity.Auth, container string, name string, segmentPrefix string, opts ObjectOpts) (err error) {
+
+	req := gorequest.New()
+
+	req.Put(objectURL(auth, container, name)).
+		Set("Content-Type", "application/octet-stream").
+		Set("X-Auth-Token", auth.Access.Token.Id).
+		Set("X-Object-Manifest", escapeObjectName(segmentPrefix))
+
+	opts.ETag = ""
+	for k, v := range opts.headers() {
+		req.Set(k, v)
+	}
+
+	resp, _, errs := req.End()
+
+	if errs != nil {
+		err = errs[len(errs)-1]
+		return
+	}
+
+	if err = openstack.CheckHttpResponseStatusCode(resp.StatusCode); err != nil {
+		return
+	}
+
+	err = nil
+	return
+}
+
+// downloadSLO streams the segments listed in the manifest read from r to w.
+func downloadSLO(auth identity.Auth, r io.Reader, w io.Writer) (size int64, err error) {
+
+	var manifest []sloManifestEntry
+	if err = json.NewDecoder(r).Decode(&manifest); err != nil {
+		return
+	}
+
+	for _, v := range manifest {
+		if len(v.Range) > 0 {
+			err = errors.New(fmt.Sprintf("segment %s: ranged segments are not supported", v.Name))
+			return
+		}
+
+		container, name := splitSegmentPath(v.Name)
+
+		var segment ObjectInfo
+		if segment, err = DownloadObject(auth, container, name, w); err != nil {
+			return
+		}
+
+		if !v.SubSLO && segment.ETag != v.Hash {
+			err = errors.New(fmt.Sprintf("segment %s changed: manifest etag %s, segment etag %s", v.Name, v.Hash, segment.ETag))
+			return
+		}
+
+		size += segment.ContentLength
+	}
+
+	err = nil
+	return
+}
+
+// downloadDLO streams the segments matching the manifest prefix to w, in
+// name order.
+func downloadDLO(auth identity.Auth, manifest string, w io.Writer) (size int64, err error) {
+
+	prefix, err := url.PathUnescape(manifest)
+	if err != nil {
+		return
+	}
+
+	container, prefix := splitSegmentPath(prefix)
+
+	segments, err := GetObjects(auth, container, ListOpts{Prefix: prefix})
+	if err != nil {
+		return
+	}
+
+	for _, v := range segments {
+		var segment ObjectInfo
+		if segment, err = DownloadObject(auth, container, v.Name, w); err != nil {
+			return
+		}
+		size += segment.ContentLength
+	}
+
+	err = nil
+	return
+}
+
+// DeleteLargeObject deletes the object, and when deleteSegments is set the
+// segments of an SLO or DLO too. Objects that are not large objects are
+// simply deleted.
+func DeleteLargeObject(auth identity.Auth, container string, name string, deleteSegments bool) (err error) {
+
+	if !deleteSegments {
+		return DeleteObject(auth, container, name)
+	}
+
+	object, err := GetObject(auth, container, name)
+	if err != nil {
+		return
+	}
+
+	switch {
+	case object.StaticLargeObject:
+		req := gorequest.New()
+
+		reqUrl := fmt.Sprintf("%s?multipart-manifest=delete",
+			objectURL(auth, container, name))
+
+		resp, body, errs := req.Delete(reqUrl).
+			Set("Accept", "application/json").
+			Set("X-Auth-Token", auth.Access.Token.Id).
+			End()
+
+		if errs != nil {
+			err = errs[len(errs)-1]
+			return
+		}
+
+		if err = openstack.CheckHttpResponseStatusCode(resp.StatusCode); err != nil {
+			return
+		}
+
+		// failures of the segment deletions are reported in the body of a
+		// 200 response
+		var r = bulkDeleteResp{}
+		if err = json.Unmarshal([]byte(body), &r); err != nil {
+			return
+		}
+
+		if !strings.HasPrefix(r.ResponseStatus, "200") {
+			err = errors.New(fmt.Sprintf("object %s/%s delete failed: %s %s %v", container, name, r.ResponseStatus, r.ResponseBody, r.Errors))
+			return
+		}
+
+	case len(object.ObjectManifest) > 0:
+		// the manifest goes first so the object is never read truncated
+		if err = DeleteObject(auth, container, name); err != nil {
+			return
+		}
+
+		var prefix string
+		if prefix, err = url.PathUnescape(object.ObjectManifest); err != nil {
+			return
+		}
+
+		segmentContainer, prefix := splitSegmentPath(prefix)
+
+		var segments []Object
+		if segments, err = GetObjects(auth, segmentContainer, ListOpts{Prefix: prefix}); err != nil {
+			return
+		}
+
+		for _, v := range segments {
+			if err = DeleteObject(auth, segmentContainer, v.Name); err != nil {
+				return
+			}
+		}
+
+	default:
+		if err = DeleteObject(auth, container, name); err != nil {
+			return
+		}
+	}
+
+	err = nil
+	return
+}
+
+// splitSegmentPath splits container/name, with or without a leading slash.
+func splitSegmentPath(path string) (container string, name string) {
+
+	path = strings.TrimPrefix(path, "/")
+
+	if i := strings.Index(path, "/"); i >= 0 {
+		return path[:i], path[i+1:]
+	}
+
+	return path, ""
+}
@@ -0,0 +1,284 @@
+package objectstorage
+
+import (
+	"crypto/md5"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gertd/go-openstack"
+	"github.com/gertd/go-openstack/identity"
+
+	"github.com/parnurzeal/gorequest"
+)
+
+// Subdir is only set, instead of the other fields, for the
+// pseudo-directories of a listing with a Delimiter.
+type Object struct {
+	Name         string `json:"name"`
+	Hash         string `json:"hash"`
+	Bytes        int64  `json:"bytes"`
+	ContentType  string `json:"content_type"`
+	LastModified string `json:"last_modified"`
+	Subdir       string `json:"subdir"`
+}
+
+// ETag is the MD5 of the content, except for large objects where it is
+// derived from the segments or the manifest. ObjectManifest is the container/prefix of the
+// segments of a dynamic large object.
+type ObjectInfo struct {
+	ContentType       string
+	ContentLength     int64
+	ETag              string
+	LastModified      string
+	StaticLargeObject bool
+	ObjectManifest    string
+	DeleteAt          int64
+	Metadata          map[string]string
+}
+
+// ObjectOpts holds the optional attributes of an uploaded object, empty
+// fields are not sent. When ETag is set Swift rejects the upload if the
+// received data does not match it. DeleteAfter (seconds) and DeleteAt
+// (Unix time) expire the object.
+type ObjectOpts struct {
+	ContentType        string
+	ContentEncoding    string
+	ContentDisposition string
+	ETag               string
+	DeleteAfter        int64
+	DeleteAt           int64
+	Metadata           map[string]string
+}
+
+func (opts ObjectOpts) headers() (headers map[string]string) {
+
+	headers = make(map[string]string)
+
+	if len(opts.ContentType) > 0 {
+		headers["Content-Type"] = opts.ContentType
+	}
+	if len(opts.ContentEncoding) > 0 {
+		headers["Content-Encoding"] = opts.ContentEncoding
+	}
+	if len(opts.ContentDisposition) > 0 {
+		headers["Content-Disposition"] = opts.ContentDisposition
+	}
+	if len(opts.ETag) > 0 {
+		headers["ETag"] = opts.ETag
+	}
+	if opts.DeleteAfter > 0 {
+		headers["X-Delete-After"] = strconv.FormatInt(opts.DeleteAfter, 10)
+	}
+	if opts.DeleteAt > 0 {
+		headers["X-Delete-At"] = strconv.FormatInt(opts.DeleteAt, 10)
+	}
+
+	setMetadataHeaders(headers, objectMetaPrefix, opts.Metadata, nil)
+
+	return headers
+}
+
+func GetObjects(auth identity.Auth, container string, opts ListOpts) (objects []Object, err error) {
+
+	marker := opts.Marker
+
+	for {
+		var page []Object
+		if err = getListing(auth, containerURL(auth, container), opts, marker, &page); err != nil {
+			return
+		}
+
+		objects = append(objects, page...)
+
+		if opts.Limit > 0 || len(page) == 0 {
+			break
+		}
+		if last := page[len(page)-1]; len(last.Name) > 0 {
+			marker = last.Name
+		} else {
+			marker = last.Subdir
+		}
+	}
+
+	err = nil
+	return
+}
+
+func GetObject(auth identity.Auth, container string, name string) (object ObjectInfo, err error) {
+
+	req := gorequest.New()
+
+	resp, _, errs := req.Head(objectURL(auth, container, name)).
+		Set("X-Auth-Token", auth.Access.Token.Id).
+		End()
+
+	if errs != nil {
+		err = errs[len(errs)-1]
+		return
+	}
+
+	if err = openstack.CheckHttpResponseStatusCode(resp.StatusCode); err != nil {
+		return
+	}
+
+	object = objectInfo(resp.Header)
+	err = nil
+	return
+}
+
+// UploadObject streams the object data from r without buffering it and
+// verifies the ETag returned by Swift against the MD5 of the data sent.
+// Pass the size when known so that it is sent as the Content-Length, -1
+// otherwise. An existing object is replaced.
+func UploadObject(auth identity.Auth, container string, name string, r io.Reader, size int64, opts ObjectOpts) (etag string, err error) {
+
+	checksum := md5.New()
+
+	req, err := http.NewRequest("PUT", objectURL(auth, container, name), io.TeeReader(r, checksum))
+	if err != nil {
+		return
+	}
+
+	req.Header.Set("X-Auth-Token", auth.Access.Token.Id)
+	for k, v := range opts.headers() {
+		req.Header.Set(k, v)
+	}
+	if size >= 0 {
+		req.ContentLength = size
+	}
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return
+	}
+	defer resp.Body.Close()
+
+	if err = openstack.CheckHttpResponseStatusCode(resp.StatusCode); err != nil {
+		return
+	}
+
+	// Swift may quote the ETag, as it does for large objects
+	etag = strings.Trim(resp.Header.Get("ETag"), `"`)
+	if sum := hex.EncodeToString(checksum.Sum(nil)); etag != sum {
+		err = errors.New(fmt.Sprintf("object %s/%s etag mismatch: sent %s, stored %s", container, name, sum, etag))
+		return
+	}
+
+	err = nil
+	return
+}
+
+// DownloadObject streams the object data to w and verifies the MD5 of the
+// data against the ETag. Large objects are read segment by segment from
+// their manifest so that every segment is verified, ContentLength is then
+// the total size. When verification fails an error is returned after the
+// data has been written, the caller must discard it.
+func DownloadObject(auth identity.Auth, container string, name string, w io.Writer) (object ObjectInfo, err error) {
+
+	reqUrl := fmt.Sprintf("%s?multipart-manifest=get",
+		objectURL(auth, container, name))
+
+	req, err := http.NewRequest("GET", reqUrl, nil)
+	if err != nil {
+		return
+	}
+
+	req.Header.Set("X-Auth-Token", auth.Access.Token.Id)
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return
+	}
+	defer resp.Body.Close()
+
+	if err = openstack.CheckHttpResponseStatusCode(resp.StatusCode); err != nil {
+		return
+	}
+
+	object = objectInfo(resp.Header)
+
+	switch {
+	case object.StaticLargeObject:
+		object.ContentLength, err = downloadSLO(auth, resp.Body, w)
+		return
+
+	case len(object.ObjectManifest) > 0:
+		object.ContentLength, err = downloadDLO(auth, object.ObjectManifest, w)
+		return
+	}
+
+	checksum := md5.New()
+	if _, err = io.Copy(io.MultiWriter(w, checksum), resp.Body); err != nil {
+		return
+	}
+
+	if sum := hex.EncodeToString(checksum.Sum(nil)); sum != object.ETag {
+		err = errors.New(fmt.Sprintf("object %s/%s etag mismatch: expected %s, got %s", container, name, object.ETag, sum))
+		return
+	}
+
+	err = nil
+	return
+}
+
+// SetObjectMetadata replaces the metadata of the object, keys not in
+// metadata are removed.
+func SetObjectMetadata(auth identity.Auth, container string, name string, metadata map[string]string) (err error) {
+
+	headers := make(map[string]string)
+	setMetadataHeaders(headers, objectMetaPrefix, metadata, nil)
+
+	return sendHeaders(auth, "POST", objectURL(auth, container, name), headers)
+}
+
+// CopyObject copies the object server side, metadata is added to the
+// metadata of the source object.
+func CopyObject(auth identity.Auth, srcContainer string, srcName string, dstContainer string, dstName string, metadata map[string]string) (err error) {
+
+	headers := map[string]string{
+		"X-Copy-From": fmt.Sprintf("/%s/%s", escapeObjectName(srcContainer), escapeObjectName(srcName)),
+	}
+	setMetadataHeaders(headers, objectMetaPrefix, metadata, nil)
+
+	return sendHeaders(auth, "PUT", objectURL(auth, dstContainer, dstName), headers)
+}
+
+func DeleteObject(auth identity.Auth, container string, name string) (err error) {
+
+	req := gorequest.New()
+
+	resp, _, errs := req.Delete(objectURL(auth, container, name)).
+		Set("X-Auth-Token", auth.Access.Token.Id).
+		End()
+
+	if errs != nil {
+		err = errs[len(errs)-1]
+		return
+	}
+
+	if err = openstack.CheckHttpResponseStatusCode(resp.StatusCode); err != nil {
+		return
+	}
+
+	err = nil
+	return
+}
+
+func objectInfo(header http.Header) ObjectInfo {
+
+	return ObjectInfo{
+		ContentType:       header.Get("Content-Type"),
+		ContentLength:     headerInt(header, "Content-Length"),
+		ETag:              strings.Trim(header.Get("ETag"), `"`),
+		LastModified:      header.Get("Last-Modified"),
+		StaticLargeObject: strings.EqualFold(header.Get("X-Static-Large-Object"), "true"),
+		ObjectManifest:    header.Get("X-Object-Manifest"),
+		DeleteAt:          headerInt(header, "X-Delete-At"),
+		Metadata:          headerMetadata(header, objectMetaPrefix),
+	}
+}
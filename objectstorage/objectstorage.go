@@ -0,0 +1,222 @@
+// Package objectstorage is a client for the Swift object storage API. The
+// endpoint is the object-store entry of the service catalog, which points
+// at the account of the project.
+package objectstorage
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/gertd/go-openstack"
+	"github.com/gertd/go-openstack/identity"
+
+	"github.com/parnurzeal/gorequest"
+)
+
+const (
+	accountMetaPrefix   = "X-Account-Meta-"
+	containerMetaPrefix = "X-Container-Meta-"
+	objectMetaPrefix    = "X-Object-Meta-"
+)
+
+// Metadata keys are returned in canonical header form, e.g. Build-Id.
+type Account struct {
+	ContainerCount int64
+	ObjectCount    int64
+	BytesUsed      int64
+	Metadata       map[string]string
+}
+
+// ListOpts narrows and pages container and object listings, empty fields
+// are not sent. With a Limit a single page is returned, continue from the
+// name of its last entry as Marker; without one every page is fetched.
+// Delimiter rolls names up to the next delimiter after Prefix into
+// pseudo-directories.
+type ListOpts struct {
+	Prefix    string
+	Delimiter string
+	Marker    string
+	EndMarker string
+	Limit     int
+}
+
+func GetAccount(auth identity.Auth) (account Account, err error) {
+
+	req := gorequest.New()
+
+	resp, _, errs := req.Head(auth.EndpointList["object-store"]).
+		Set("X-Auth-Token", auth.Access.Token.Id).
+		End()
+
+	if errs != nil {
+		err = errs[len(errs)-1]
+		return
+	}
+
+	if err = openstack.CheckHttpResponseStatusCode(resp.StatusCode); err != nil {
+		return
+	}
+
+	account = Account{
+		ContainerCount: headerInt(resp.Header, "X-Account-Container-Count"),
+		ObjectCount:    headerInt(resp.Header, "X-Account-Object-Count"),
+		BytesUsed:      headerInt(resp.Header, "X-Account-Bytes-Used"),
+		Metadata:       headerMetadata(resp.Header, accountMetaPrefix),
+	}
+	err = nil
+	return
+}
+
+// UpdateAccountMetadata sets metadata on the account, keys not in metadata
+// are kept unless listed in remove.
+func UpdateAccountMetadata(auth identity.Auth, metadata map[string]string, remove []string) (err error) {
+
+	headers := make(map[string]string)
+	setMetadataHeaders(headers, accountMetaPrefix, metadata, remove)
+
+	return sendHeaders(auth, "POST", auth.EndpointList["object-store"], headers)
+}
+
+// sendHeaders sends a bodiless request carrying headers. It does not use
+// gorequest, which always sets a JSON Content-Type: Swift would store it as
+// the content type of the object on a POST or a copy.
+func sendHeaders(auth identity.Auth, method string, reqUrl string, headers map[string]string) (err error) {
+
+	req, err := http.NewRequest(method, reqUrl, nil)
+	if err != nil {
+		return
+	}
+
+	req.Header.Set("X-Auth-Token", auth.Access.Token.Id)
+	for k, v := range headers {
+		req.Header.Set(k, v)
+	}
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return
+	}
+	defer resp.Body.Close()
+
+	if err = openstack.CheckHttpResponseStatusCode(resp.StatusCode); err != nil {
+		return
+	}
+
+	err = nil
+	return
+}
+
+// getListing fetches one page of a JSON listing into page, starting after
+// marker.
+func getListing(auth identity.Auth, baseUrl string, opts ListOpts, marker string, page interface{}) (err error) {
+
+	req := gorequest.New()
+
+	query := url.Values{}
+	query.Set("format", "json")
+	if len(opts.Prefix) > 0 {
+		query.Set("prefix", opts.Prefix)
+	}
+	if len(opts.Delimiter) > 0 {
+		query.Set("delimiter", opts.Delimiter)
+	}
+	if len(marker) > 0 {
+		query.Set("marker", marker)
+	}
+	if len(opts.EndMarker) > 0 {
+		query.Set("end_marker", opts.EndMarker)
+	}
+	if opts.Limit > 0 {
+		query.Set("limit", strconv.Itoa(opts.Limit))
+	}
+
+	reqUrl := fmt.Sprintf("%s?%s",
+		baseUrl,
+		query.Encode())
+
+	resp, body, errs := req.Get(reqUrl).
+		Set("Accept", "application/json").
+		Set("X-Auth-Token", auth.Access.Token.Id).
+		End()
+
+	if errs != nil {
+		err = errs[len(errs)-1]
+		return
+	}
+
+	if err = openstack.CheckHttpResponseStatusCode(resp.StatusCode); err != nil {
+		return
+	}
+
+	// an empty container or account answers 204 without a body
+	if len(body) == 0 {
+		return
+	}
+
+	if err = json.Unmarshal([]byte(body), page); err != nil {
+		return
+	}
+
+	err = nil
+	return
+}
+
+func containerURL(auth identity.Auth, container string) string {
+
+	return fmt.Sprintf("%s/%s",
+		auth.EndpointList["object-store"],
+		url.PathEscape(container))
+}
+
+func objectURL(auth identity.Auth, container string, name string) string {
+
+	return fmt.Sprintf("%s/%s",
+		containerURL(auth, container),
+		escapeObjectName(name))
+}
+
+// escapeObjectName escapes every path segment of the object name, slashes
+// are kept since pseudo-directories are part of the name.
+func escapeObjectName(name string) string {
+
+	segments := strings.Split(name, "/")
+	for i, v := range segments {
+		segments[i] = url.PathEscape(v)
+	}
+
+	return strings.Join(segments, "/")
+}
+
+func headerInt(header http.Header, key string) int64 {
+
+	n, _ := strconv.ParseInt(header.Get(key), 10, 64)
+	return n
+}
+
+func headerMetadata(header http.Header, prefix string) (metadata map[string]string) {
+
+	metadata = make(map[string]string)
+
+	for k, v := range header {
+		if len(v) > 0 && strings.HasPrefix(k, prefix) {
+			metadata[k[len(prefix):]] = v[0]
+		}
+	}
+
+	return metadata
+}
+
+func setMetadataHeaders(headers map[string]string, prefix string, metadata map[string]string, remove []string) {
+
+	for k, v := range metadata {
+		headers[prefix+k] = v
+	}
+
+	for _, k := range remove {
+		headers["X-Remove-"+strings.TrimPrefix(prefix, "X-")+k] = "x"
+	}
+}
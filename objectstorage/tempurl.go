@@ -0,0 +1,224 @@
+package objectstorage
+
+import (
+	"crypto/hmac"
+	"crypto/sha1"
+	"crypto/sha256"
+	"crypto/sha512"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"hash"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gertd/go-openstack/identity"
+)
+
+const (
+	DigestSHA1   = "sha1"
+	DigestSHA256 = "sha256"
+	DigestSHA512 = "sha512"
+
+	tempURLKey  = "Temp-URL-Key"
+	tempURLKey2 = "Temp-URL-Key-2"
+)
+
+// TempURLOpts describes a temporary URL. Key is one of the temp URL keys of
+// the account or container, Digest defaults to DigestSHA256 and must be
+// allowed by the cluster. With a Prefix the signature is valid for every
+// object whose name starts with it. IPRange, an address or CIDR, restricts
+// the clients allowed to use the URL.
+type TempURLOpts struct {
+	Key     string
+	Method  string
+	Expires time.Time
+	Digest  string
+	Prefix  string
+	IPRange string
+}
+
+// FormPostOpts describes an HTML form upload to the objects starting with a
+// prefix. Key is one of the temp URL keys of the account or container and
+// Digest defaults to DigestSHA256. Redirect is where the browser is sent
+// after the upload, it may be empty.
+type FormPostOpts struct {
+	Key          string
+	Expires      time.Time
+	Digest       string
+	Redirect     string
+	MaxFileSize  int64
+	MaxFileCount int
+}
+
+// FormPost holds the form action and the hidden fields the form must post
+// along with the files.
+type FormPost struct {
+	URL    string
+	Fields map[string]string
+}
+
+// TempURL returns a URL giving access to the object with opts.Method until
+// opts.Expires, without authentication. It is computed locally, only the
+// endpoint is taken from auth.
+func TempURL(auth identity.Auth, container string, name string, opts TempURLOpts) (tempURL string, err error) {
+
+	endpoint, err := url.Parse(auth.EndpointList["object-store"])
+	if err != nil {
+		return
+	}
+
+	if len(opts.Prefix) > 0 && !strings.HasPrefix(name, opts.Prefix) {
+		err = errors.New(fmt.Sprintf("object %s does not start with prefix %s", name, opts.Prefix))
+		return
+	}
+
+	method := strings.ToUpper(opts.Method)
+	if len(method) == 0 {
+		method = "GET"
+	}
+	expires := strconv.FormatInt(opts.Expires.Unix(), 10)
+
+	path := strings.TrimRight(endpoint.Path, "/") + "/" + container + "/"
+	if len(opts.Prefix) > 0 {
+		path = "prefix:" + path + opts.Prefix
+	} else {
+		path += name
+	}
+
+	message := method + "\n" + expires + "\n" + path
+	if len(opts.IPRange) > 0 {
+		message = "ip=" + opts.IPRange + "\n" + message
+	}
+
+	signature, err := sign(opts.Key, opts.Digest, message)
+	if err != nil {
+		return
+	}
+
+	query := url.Values{}
+	query.Set("temp_url_sig", signature)
+	query.Set("temp_url_expires", expires)
+	if len(opts.Prefix) > 0 {
+		query.Set("temp_url_prefix", opts.Prefix)
+	}
+	if len(opts.IPRange) > 0 {
+		query.Set("temp_url_ip_range", opts.IPRange)
+	}
+
+	tempURL = fmt.Sprintf("%s?%s",
+		objectURL(auth, container, name),
+		query.Encode())
+
+	err = nil
+	return
+}
+
+// FormPostSignature returns the form action and signed fields to upload
+// files as objects starting with prefix. It is computed locally, only the
+// endpoint is taken from auth.
+func FormPostSignature(auth identity.Auth, container string, prefix string, opts FormPostOpts) (form FormPost, err error) {
+
+	endpoint, err := url.Parse(auth.EndpointList["object-store"])
+	if err != nil {
+		return
+	}
+
+	path := strings.TrimRight(endpoint.Path, "/") + "/" + container + "/" + prefix
+
+	expires := strconv.FormatInt(opts.Expires.Unix(), 10)
+	maxFileSize := strconv.FormatInt(opts.MaxFileSize, 10)
+	maxFileCount := strconv.Itoa(opts.MaxFileCount)
+
+	message := strings.Join([]string{path, opts.Redirect, maxFileSize, maxFileCount, expires}, "\n")
+
+	signature, err := sign(opts.Key, opts.Digest, message)
+	if err != nil {
+		return
+	}
+
+	form = FormPost{
+		URL: objectURL(auth, container, prefix),
+		Fields: map[string]string{
+			"redirect":       opts.Redirect,
+			"max_file_size":  maxFileSize,
+			"max_file_count": maxFileCount,
+			"expires":        expires,
+			"signature":      signature,
+		},
+	}
+	err = nil
+	return
+}
+
+// SetAccountTempURLKeys sets the temp URL keys of the account, an empty key
+// is removed. The second key allows rotating keys without invalidating the
+// URLs signed with the first one.
+func SetAccountTempURLKeys(auth identity.Auth, key string, key2 string) (err error) {
+
+	metadata, remove := tempURLKeys(key, key2)
+
+	return UpdateAccountMetadata(auth, metadata, remove)
+}
+
+// SetContainerTempURLKeys sets the temp URL keys of the container, they
+// let the holder sign URLs for this container only. An empty key is
+// removed.
+func SetContainerTempURLKeys(auth identity.Auth, container string, key string, key2 string) (err error) {
+
+	metadata, remove := tempURLKeys(key, key2)
+
+	return UpdateContainer(auth, container, ContainerOpts{Metadata: metadata, RemoveMetadata: remove})
+}
+
+func tempURLKeys(key string, key2 string) (metadata map[string]string, remove []string) {
+
+	metadata = make(map[string]string)
+
+	for k, v := range map[string]string{tempURLKey: key, tempURLKey2: key2} {
+		if len(v) > 0 {
+			metadata[k] = v
+		} else {
+			remove = append(remove, k)
+		}
+	}
+
+	return metadata, remove
+}
+
+func sign(key string, digest string, message string) (signature string, err error) {
+
+	if len(key) == 0 {
+		err = errors.New("temp URL key is empty")
+		return
+	}
+
+	var h func() hash.Hash
+	switch digest {
+	case DigestSHA1:
+		h = sha1.New
+	case DigestSHA256, "":
+		h = sha256.New
+	case DigestSHA512:
+		h = sha512.New
+	default:
+		err = errors.New(fmt.Sprintf("unsupported digest %s", digest))
+		return
+	}
+
+	mac := hmac.New(h, []byte(key))
+	mac.Write([]byte(message))
+
+	// Swift tells SHA-1 and SHA-256 apart by the length of a hex signature,
+	// SHA-512 must be sent prefixed and base64 encoded
+	if digest == DigestSHA512 {
+		signature = DigestSHA512 + ":" + base64.URLEncoding.EncodeToString(mac.Sum(nil))
+	} else {
+		signature = hex.EncodeToString(mac.Sum(nil))
+	}
+	err = nil
+	return
+}
@@ -0,0 +1,94 @@
+package objectstorage
+
+import (
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gertd/go-openstack/identity"
+)
+
+// published in the Swift tempurl middleware documentation
+const (
+	swiftDocKey     = "mykey"
+	swiftDocExpires = 1516741234
+	swiftDocSHA512  = "sha512:ZrSijn0GyDhsv1ltIj9hWUTrbAeE45NcKXyBaz7aPbSMvROQ4jtYH4nRAmm5ErY2X11Yc1Yhy2OMCyN3yueeXg=="
+)
+
+func testAuth() (auth identity.Auth) {
+
+	auth.EndpointList = map[string]string{"object-store": "https://swift-cluster.example.com/v1/AUTH_account"}
+	return auth
+}
+
+func TestTempURLSHA512(t *testing.T) {
+
+	tempURL, err := TempURL(testAuth(), "container", "object", TempURLOpts{
+		Key:     swiftDocKey,
+		Method:  "GET",
+		Expires: time.Unix(swiftDocExpires, 0),
+		Digest:  DigestSHA512,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	u, err := url.Parse(tempURL)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if u.Path != "/v1/AUTH_account/container/object" {
+		t.Errorf("path = %s", u.Path)
+	}
+	if sig := u.Query().Get("temp_url_sig"); sig != swiftDocSHA512 {
+		t.Errorf("temp_url_sig = %s, want %s", sig, swiftDocSHA512)
+	}
+	if expires := u.Query().Get("temp_url_expires"); expires != "1516741234" {
+		t.Errorf("temp_url_expires = %s", expires)
+	}
+}
+
+func TestTempURLPrefix(t *testing.T) {
+
+	opts := TempURLOpts{Key: swiftDocKey, Expires: time.Unix(swiftDocExpires, 0), Prefix: "logs/"}
+
+	if _, err := TempURL(testAuth(), "container", "other/object", opts); err == nil {
+		t.Error("object outside the prefix was signed")
+	}
+
+	tempURL, err := TempURL(testAuth(), "container", "logs/object", opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	u, _ := url.Parse(tempURL)
+	if prefix := u.Query().Get("temp_url_prefix"); prefix != "logs/" {
+		t.Errorf("temp_url_prefix = %s", prefix)
+	}
+	if sig := u.Query().Get("temp_url_sig"); len(sig) != 64 {
+		t.Errorf("sha256 signature %s is not 64 hex digits", sig)
+	}
+}
+
+func TestFormPostSignatureSHA512(t *testing.T) {
+
+	form, err := FormPostSignature(testAuth(), "container", "uploads/", FormPostOpts{
+		Key:          swiftDocKey,
+		Expires:      time.Unix(swiftDocExpires, 0),
+		Digest:       DigestSHA512,
+		MaxFileSize:  1024,
+		MaxFileCount: 1,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !strings.HasPrefix(form.Fields["signature"], "sha512:") {
+		t.Errorf("signature = %s", form.Fields["signature"])
+	}
+	if form.URL != "https://swift-cluster.example.com/v1/AUTH_account/container/uploads/" {
+		t.Errorf("url = %s", form.URL)
+	}
+}